@@ -0,0 +1,222 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// OverflowPolicy 용량이 찬 BlockingQueue 에 Push 할 때의 동작
+type OverflowPolicy int
+
+const (
+	OverflowBlock      OverflowPolicy = 0 + iota // 자리가 날 때까지 대기
+	OverflowDropOldest                           // 가장 오래된 아이템을 버리고 넣음
+	OverflowFail                                 // ErrQueueFull 리턴
+)
+
+var (
+	ErrQueueFull   = errors.New("container: queue is full")
+	ErrQueueClosed = errors.New("container: queue is closed")
+)
+
+// BlockingQueue 비었을 때 Pop 이 대기하는 큐. capacity 가 0 이면 용량 제한 없음
+// Close 이후에는 Push 가 ErrQueueClosed 를 리턴하고, Pop 은 남은 아이템을 모두 꺼낸 뒤 ErrQueueClosed 를 리턴함
+type BlockingQueue[T any] struct {
+	items    []T
+	head     int
+	capacity int
+	policy   OverflowPolicy
+	closed   bool
+	lock     sync.Mutex
+	notEmpty signal
+	notFull  signal
+}
+
+func NewBlockingQueue[T any](capacity int, policy OverflowPolicy) *BlockingQueue[T] {
+	result := new(BlockingQueue[T])
+	result.capacity = capacity
+	result.policy = policy
+
+	return result
+}
+
+// Push OverflowBlock 정책이면 자리가 날 때까지 대기함
+func (q *BlockingQueue[T]) Push(item T) error {
+	return q.PushWait(context.Background(), item)
+}
+
+// PushWait OverflowBlock 정책에서 ctx 가 끝나면 ctx.Err() 를 리턴
+func (q *BlockingQueue[T]) PushWait(ctx context.Context, item T) error {
+	q.lock.Lock()
+
+	for {
+		if q.closed {
+			q.lock.Unlock()
+			return ErrQueueClosed
+		}
+
+		if q.capacity <= 0 || q.length() < q.capacity {
+			break
+		}
+
+		switch q.policy {
+		case OverflowFail:
+			q.lock.Unlock()
+			return ErrQueueFull
+		case OverflowDropOldest:
+			q.popFront()
+			continue
+		}
+
+		wait := q.notFull.wait()
+		q.lock.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		q.lock.Lock()
+	}
+
+	q.items = append(q.items, item)
+	q.notEmpty.broadcast()
+	q.lock.Unlock()
+
+	return nil
+}
+
+// TryPop 비었으면 바로 (zero, false) 리턴
+func (q *BlockingQueue[T]) TryPop() (T, bool) {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	if q.length() == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return q.popFront(), true
+}
+
+// PopWait 아이템이 들어올 때까지 대기. ctx 가 끝나면 ctx.Err(), 닫힌 큐가 비었으면 ErrQueueClosed
+func (q *BlockingQueue[T]) PopWait(ctx context.Context) (T, error) {
+	items, err := q.PopNWait(ctx, 1)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return items[0], nil
+}
+
+// PopN 대기하지 않고 최대 n 개를 꺼냄
+func (q *BlockingQueue[T]) PopN(n int) []T {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	return q.popFrontN(n)
+}
+
+// PopNWait 최소 한개가 들어올 때까지 대기한 뒤 최대 n 개를 꺼냄
+func (q *BlockingQueue[T]) PopNWait(ctx context.Context, n int) ([]T, error) {
+	q.lock.Lock()
+
+	for q.length() == 0 {
+		if q.closed {
+			q.lock.Unlock()
+			return nil, ErrQueueClosed
+		}
+
+		wait := q.notEmpty.wait()
+		q.lock.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		q.lock.Lock()
+	}
+
+	result := q.popFrontN(n)
+	q.lock.Unlock()
+
+	return result, nil
+}
+
+// Drain 남아있는 아이템을 모두 꺼냄
+func (q *BlockingQueue[T]) Drain() []T {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	return q.popFrontN(q.length())
+}
+
+// Close 대기중인 Push, Pop 을 모두 깨움
+func (q *BlockingQueue[T]) Close() {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	q.closed = true
+	q.notEmpty.broadcast()
+	q.notFull.broadcast()
+}
+
+func (q *BlockingQueue[T]) IsClosed() bool {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	return q.closed
+}
+
+func (q *BlockingQueue[T]) Len() int {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	return q.length()
+}
+
+func (q *BlockingQueue[T]) Cap() int {
+	return q.capacity
+}
+
+func (q *BlockingQueue[T]) length() int {
+	return len(q.items) - q.head
+}
+
+func (q *BlockingQueue[T]) popFront() T {
+	var zero T
+
+	v := q.items[q.head]
+	q.items[q.head] = zero
+	q.head++
+
+	// 메모리 회수: SafeQueue 와 같은 방식
+	if q.head*2 >= len(q.items) {
+		q.items = append(q.items[:0:0], q.items[q.head:]...)
+		q.head = 0
+	}
+
+	q.notFull.broadcast()
+	return v
+}
+
+func (q *BlockingQueue[T]) popFrontN(n int) []T {
+	if n > q.length() {
+		n = q.length()
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	result := make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		result = append(result, q.popFront())
+	}
+
+	return result
+}
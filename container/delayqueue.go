@@ -0,0 +1,149 @@
+package container
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// DelayItem DelayQueue.Push 가 돌려주는 핸들. Remove 에 사용
+type DelayItem[T any] struct {
+	Value    T
+	Deadline time.Time
+	index    int
+}
+
+// DelayQueue Deadline 이 지난 아이템만 꺼낼 수 있는 큐. 재시도 큐나 타이머 기반 스케쥴러에 사용
+// Close 이후에는 Push 가 ErrQueueClosed 를 리턴하고, PopWait 은 Deadline 이 지난 아이템을 모두 꺼낸 뒤 ErrQueueClosed 를 리턴함
+type DelayQueue[T any] struct {
+	lock    sync.Mutex
+	heap    indexedHeap[*DelayItem[T]]
+	changed signal
+	closed  bool
+}
+
+func NewDelayQueue[T any]() *DelayQueue[T] {
+	result := new(DelayQueue[T])
+	result.heap.less = func(a, b *DelayItem[T]) bool {
+		return a.Deadline.Before(b.Deadline)
+	}
+	result.heap.setIndex = func(item *DelayItem[T], index int) {
+		item.index = index
+	}
+
+	return result
+}
+
+func (q *DelayQueue[T]) Push(value T, deadline time.Time) (*DelayItem[T], error) {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	if q.closed {
+		return nil, ErrQueueClosed
+	}
+
+	item := &DelayItem[T]{Value: value, Deadline: deadline}
+	heap.Push(&q.heap, item)
+	q.changed.broadcast()
+
+	return item, nil
+}
+
+func (q *DelayQueue[T]) PushAfter(value T, delay time.Duration) (*DelayItem[T], error) {
+	return q.Push(value, time.Now().Add(delay))
+}
+
+// Remove 아직 꺼내지지 않은 아이템을 취소. 이미 꺼내졌으면 false
+func (q *DelayQueue[T]) Remove(item *DelayItem[T]) bool {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	if item == nil || item.index < 0 || item.index >= q.heap.Len() || q.heap.items[item.index] != item {
+		return false
+	}
+
+	heap.Remove(&q.heap, item.index)
+	q.changed.broadcast()
+	return true
+}
+
+// TryPop Deadline 이 지난 아이템이 없으면 바로 (zero, false)
+func (q *DelayQueue[T]) TryPop() (T, bool) {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	if q.heap.Len() == 0 || q.heap.items[0].Deadline.After(time.Now()) {
+		var zero T
+		return zero, false
+	}
+
+	return heap.Pop(&q.heap).(*DelayItem[T]).Value, true
+}
+
+// PopWait 가장 빠른 Deadline 까지 대기. 더 빠른 아이템이 들어오면 그 아이템 기준으로 다시 대기함
+// 닫힌 큐는 Deadline 이 지난 아이템을 먼저 꺼내고, 더 없으면 남은 아이템을 기다리지 않고 ErrQueueClosed 를 리턴
+func (q *DelayQueue[T]) PopWait(ctx context.Context) (T, error) {
+	var zero T
+
+	for {
+		q.lock.Lock()
+		var remain time.Duration
+		if q.heap.Len() > 0 {
+			remain = time.Until(q.heap.items[0].Deadline)
+			if remain <= 0 {
+				result := heap.Pop(&q.heap).(*DelayItem[T]).Value
+				q.lock.Unlock()
+				return result, nil
+			}
+		}
+
+		if q.closed {
+			q.lock.Unlock()
+			return zero, ErrQueueClosed
+		}
+
+		var timer *time.Timer
+		if q.heap.Len() > 0 {
+			timer = time.NewTimer(remain)
+		}
+
+		wait := q.changed.wait()
+		q.lock.Unlock()
+
+		var expired <-chan time.Time
+		if timer != nil {
+			expired = timer.C
+		}
+
+		select {
+		case <-wait:
+		case <-expired:
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return zero, ctx.Err()
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+func (q *DelayQueue[T]) Len() int {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	return q.heap.Len()
+}
+
+// Close 이후의 Push 를 막고 대기중인 PopWait 를 모두 깨움
+func (q *DelayQueue[T]) Close() {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	q.closed = true
+	q.changed.broadcast()
+}
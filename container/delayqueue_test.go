@@ -0,0 +1,85 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDelayQueueOrder(t *testing.T) {
+	q := NewDelayQueue[int]()
+	now := time.Now()
+	_, _ = q.Push(2, now.Add(20*time.Millisecond))
+	_, _ = q.Push(1, now.Add(10*time.Millisecond))
+	removed, _ := q.Push(3, now.Add(5*time.Millisecond))
+
+	if _, ok := q.TryPop(); ok {
+		t.Fatal("TryPop returned an item before its deadline")
+	}
+	if !q.Remove(removed) || q.Remove(removed) {
+		t.Fatal("Remove should succeed exactly once")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for _, want := range []int{1, 2} {
+		got, err := q.PopWait(ctx)
+		if err != nil || got != want {
+			t.Fatalf("PopWait = %d, %v, want %d", got, err, want)
+		}
+	}
+}
+
+func TestDelayQueueClose(t *testing.T) {
+	q := NewDelayQueue[int]()
+	now := time.Now()
+	_, _ = q.Push(1, now.Add(-time.Second))
+	_, _ = q.Push(2, now.Add(-time.Millisecond))
+	_, _ = q.Push(3, now.Add(time.Hour))
+	q.Close()
+
+	if _, err := q.PushAfter(4, 0); !errors.Is(err, ErrQueueClosed) {
+		t.Fatalf("Push after Close err = %v, want ErrQueueClosed", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for _, want := range []int{1, 2} {
+		got, err := q.PopWait(ctx)
+		if err != nil || got != want {
+			t.Fatalf("PopWait = %d, %v, want %d", got, err, want)
+		}
+	}
+	if _, err := q.PopWait(ctx); !errors.Is(err, ErrQueueClosed) {
+		t.Fatalf("PopWait err = %v, want ErrQueueClosed", err)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("Len = %d, want the undue item to remain", q.Len())
+	}
+}
+
+func TestDelayQueueCloseWakesWaiter(t *testing.T) {
+	q := NewDelayQueue[int]()
+	_, _ = q.PushAfter(1, time.Hour)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := q.PopWait(context.Background())
+		done <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	q.Close()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrQueueClosed) {
+			t.Fatalf("PopWait err = %v, want ErrQueueClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close did not wake PopWait")
+	}
+}
@@ -0,0 +1,246 @@
+package container
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// EvictReason LRUCache 에서 아이템이 빠진 이유
+type EvictReason int
+
+const (
+	EvictCapacity EvictReason = 0 + iota // 개수 또는 비용 한도를 넘어서 밀려남
+	EvictExpired                         // TTL 만료
+	EvictDeleted                         // Delete, Purge 로 지워짐
+	EvictReplaced                        // 같은 키로 새 값이 들어옴
+)
+
+// LRUOptions MaxEntries, MaxCost 가 0 이면 해당 한도 없음. Cost 가 nil 이면 아이템당 비용 1 (MaxCost 보다 큰 아이템은 들어오자마자 밀려남)
+// TTL 은 Set 의 기본 만료 시간 (0 이면 만료 없음). OnEvict 는 cache lock 밖에서 호출됨
+type LRUOptions[K comparable, V any] struct {
+	MaxEntries int
+	MaxCost    int64
+	Cost       func(key K, value V) int64
+	TTL        time.Duration
+	OnEvict    func(key K, value V, reason EvictReason)
+}
+
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+	Cost      int64
+}
+
+type lruEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	cost   int64
+	expire time.Time
+}
+
+type evicted[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+type LRUCache[K comparable, V any] struct {
+	options LRUOptions[K, V]
+	lock    sync.Mutex
+	order   *list.List
+	items   map[K]*list.Element
+	cost    int64
+	stats   CacheStats
+}
+
+func NewLRUCache[K comparable, V any](options LRUOptions[K, V]) *LRUCache[K, V] {
+	result := new(LRUCache[K, V])
+	result.options = options
+	result.order = list.New()
+	result.items = make(map[K]*list.Element)
+
+	return result
+}
+
+// Get 찾으면 가장 최근 사용으로 옮김. 만료된 아이템은 이 시점에 제거됨
+func (c *LRUCache[K, V]) Get(key K) (V, bool) {
+	var (
+		zero    V
+		removed []evicted[K, V]
+	)
+
+	c.lock.Lock()
+	elem, ok := c.items[key]
+	if ok {
+		entry := elem.Value.(*lruEntry[K, V])
+		if entry.expired(time.Now()) {
+			removed = append(removed, c.remove(elem, EvictExpired))
+			ok = false
+		} else {
+			c.order.MoveToFront(elem)
+			c.stats.Hits++
+			c.lock.Unlock()
+			return entry.value, true
+		}
+	}
+	c.stats.Misses++
+	c.lock.Unlock()
+
+	c.notify(removed)
+	return zero, false
+}
+
+// Peek 순서와 통계를 바꾸지 않고 조회
+func (c *LRUCache[K, V]) Peek(key K) (V, bool) {
+	var zero V
+
+	defer c.lock.Unlock()
+
+	c.lock.Lock()
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*lruEntry[K, V])
+		if !entry.expired(time.Now()) {
+			return entry.value, true
+		}
+	}
+
+	return zero, false
+}
+
+func (c *LRUCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.options.TTL)
+}
+
+// SetWithTTL ttl 이 0 이면 만료 없음
+func (c *LRUCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var removed []evicted[K, V]
+
+	entry := &lruEntry[K, V]{key: key, value: value, cost: 1}
+	if c.options.Cost != nil {
+		entry.cost = c.options.Cost(key, value)
+	}
+	if ttl > 0 {
+		entry.expire = time.Now().Add(ttl)
+	}
+
+	c.lock.Lock()
+	if elem, ok := c.items[key]; ok {
+		removed = append(removed, c.remove(elem, EvictReplaced))
+	}
+
+	c.items[key] = c.order.PushFront(entry)
+	c.cost += entry.cost
+
+	now := time.Now()
+	for c.overLimit() {
+		back := c.order.Back()
+		reason := EvictCapacity
+		if back.Value.(*lruEntry[K, V]).expired(now) {
+			reason = EvictExpired
+		}
+		removed = append(removed, c.remove(back, reason))
+	}
+	c.lock.Unlock()
+
+	c.notify(removed)
+}
+
+func (c *LRUCache[K, V]) Delete(key K) bool {
+	var removed []evicted[K, V]
+
+	c.lock.Lock()
+	elem, ok := c.items[key]
+	if ok {
+		removed = append(removed, c.remove(elem, EvictDeleted))
+	}
+	c.lock.Unlock()
+
+	c.notify(removed)
+	return ok
+}
+
+// RemoveExpired 만료된 아이템을 모두 지우고 개수를 리턴. 주기적으로 호출하면 메모리를 빨리 회수할 수 있음
+func (c *LRUCache[K, V]) RemoveExpired() int {
+	var removed []evicted[K, V]
+
+	c.lock.Lock()
+	now := time.Now()
+	for elem := c.order.Back(); elem != nil; {
+		prev := elem.Prev()
+		if elem.Value.(*lruEntry[K, V]).expired(now) {
+			removed = append(removed, c.remove(elem, EvictExpired))
+		}
+		elem = prev
+	}
+	c.lock.Unlock()
+
+	c.notify(removed)
+	return len(removed)
+}
+
+func (c *LRUCache[K, V]) Purge() {
+	var removed []evicted[K, V]
+
+	c.lock.Lock()
+	for elem := c.order.Back(); elem != nil; elem = c.order.Back() {
+		removed = append(removed, c.remove(elem, EvictDeleted))
+	}
+	c.lock.Unlock()
+
+	c.notify(removed)
+}
+
+func (c *LRUCache[K, V]) Len() int {
+	defer c.lock.Unlock()
+
+	c.lock.Lock()
+	return c.order.Len()
+}
+
+func (c *LRUCache[K, V]) Stats() CacheStats {
+	defer c.lock.Unlock()
+
+	c.lock.Lock()
+	result := c.stats
+	result.Entries = c.order.Len()
+	result.Cost = c.cost
+
+	return result
+}
+
+func (c *LRUCache[K, V]) overLimit() bool {
+	if c.options.MaxEntries > 0 && c.order.Len() > c.options.MaxEntries {
+		return true
+	}
+	return c.options.MaxCost > 0 && c.cost > c.options.MaxCost
+}
+
+func (c *LRUCache[K, V]) remove(elem *list.Element, reason EvictReason) evicted[K, V] {
+	entry := elem.Value.(*lruEntry[K, V])
+	c.order.Remove(elem)
+	delete(c.items, entry.key)
+	c.cost -= entry.cost
+
+	if reason == EvictCapacity || reason == EvictExpired {
+		c.stats.Evictions++
+	}
+
+	return evicted[K, V]{key: entry.key, value: entry.value, reason: reason}
+}
+
+func (c *LRUCache[K, V]) notify(removed []evicted[K, V]) {
+	if c.options.OnEvict == nil {
+		return
+	}
+
+	for _, e := range removed {
+		c.options.OnEvict(e.key, e.value, e.reason)
+	}
+}
+
+func (e *lruEntry[K, V]) expired(now time.Time) bool {
+	return !e.expire.IsZero() && now.After(e.expire)
+}
@@ -0,0 +1,262 @@
+package container
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"sync"
+)
+
+const defaultShardCount = 32
+
+type mapShard[K comparable, V any] struct {
+	lock  sync.RWMutex
+	items map[K]V
+}
+
+// Map 샤드별로 RWMutex 를 가진 동시성 맵
+// 키 해시는 문자열, 정수 타입은 바로 계산하고 그 외 타입은 reflect 로 == 와 같은 기준 (포인터, 채널은 주소) 으로 계산하므로 조금 느림
+type Map[K comparable, V any] struct {
+	seed   maphash.Seed
+	mask   uint64
+	shards []mapShard[K, V]
+}
+
+// NewMap shardCount 는 2 의 거듭제곱으로 올림. 0 이하이면 32
+func NewMap[K comparable, V any](shardCount int) *Map[K, V] {
+	if shardCount <= 0 {
+		shardCount = defaultShardCount
+	}
+
+	size := 1
+	for size < shardCount {
+		size <<= 1
+	}
+
+	result := new(Map[K, V])
+	result.seed = maphash.MakeSeed()
+	result.mask = uint64(size - 1)
+	result.shards = make([]mapShard[K, V], size)
+	for i := range result.shards {
+		result.shards[i].items = make(map[K]V)
+	}
+
+	return result
+}
+
+func (m *Map[K, V]) shard(key K) *mapShard[K, V] {
+	var h uint64
+
+	switch k := any(key).(type) {
+	case string:
+		h = maphash.String(m.seed, k)
+	case int:
+		h = mixHash(uint64(k))
+	case int8:
+		h = mixHash(uint64(k))
+	case int16:
+		h = mixHash(uint64(k))
+	case int32:
+		h = mixHash(uint64(k))
+	case int64:
+		h = mixHash(uint64(k))
+	case uint:
+		h = mixHash(uint64(k))
+	case uint8:
+		h = mixHash(uint64(k))
+	case uint16:
+		h = mixHash(uint64(k))
+	case uint32:
+		h = mixHash(uint64(k))
+	case uint64:
+		h = mixHash(k)
+	case uintptr:
+		h = mixHash(uint64(k))
+	default:
+		var hash maphash.Hash
+		hash.SetSeed(m.seed)
+		writeHash(&hash, reflect.ValueOf(&key).Elem())
+		h = hash.Sum64()
+	}
+
+	return &m.shards[h&m.mask]
+}
+
+// writeHash == 로 같은 키는 같은 해시가 되도록 값을 씀. 포인터와 채널은 가리키는 값이 아니라 주소, -0.0 과 +0.0 은 같은 값
+func writeHash(hash *maphash.Hash, v reflect.Value) {
+	var buf [8]byte
+	writeUint := func(x uint64) {
+		binary.LittleEndian.PutUint64(buf[:], x)
+		_, _ = hash.Write(buf[:])
+	}
+	writeFloat := func(f float64) {
+		if f == 0 {
+			f = 0
+		}
+		writeUint(math.Float64bits(f))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			_ = hash.WriteByte(1)
+		} else {
+			_ = hash.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeFloat(real(c))
+		writeFloat(imag(c))
+	case reflect.String:
+		_, _ = hash.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint(uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			_ = hash.WriteByte(0)
+			return
+		}
+		writeHash(hash, v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			writeHash(hash, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			writeHash(hash, v.Field(i))
+		}
+	}
+}
+
+// mixHash 연속된 정수 키가 같은 샤드에 몰리지 않도록 섞음 (splitmix64)
+func mixHash(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func (m *Map[K, V]) Load(key K) (V, bool) {
+	s := m.shard(key)
+	defer s.lock.RUnlock()
+
+	s.lock.RLock()
+	v, ok := s.items[key]
+	return v, ok
+}
+
+func (m *Map[K, V]) Store(key K, value V) {
+	s := m.shard(key)
+	defer s.lock.Unlock()
+
+	s.lock.Lock()
+	s.items[key] = value
+}
+
+// LoadOrStore 키가 있으면 기존 값과 true, 없으면 value 를 넣고 value 와 false 를 리턴
+func (m *Map[K, V]) LoadOrStore(key K, value V) (V, bool) {
+	s := m.shard(key)
+	defer s.lock.Unlock()
+
+	s.lock.Lock()
+	if v, ok := s.items[key]; ok {
+		return v, true
+	}
+
+	s.items[key] = value
+	return value, false
+}
+
+func (m *Map[K, V]) LoadAndDelete(key K) (V, bool) {
+	s := m.shard(key)
+	defer s.lock.Unlock()
+
+	s.lock.Lock()
+	v, ok := s.items[key]
+	if ok {
+		delete(s.items, key)
+	}
+	return v, ok
+}
+
+func (m *Map[K, V]) Delete(key K) {
+	s := m.shard(key)
+	defer s.lock.Unlock()
+
+	s.lock.Lock()
+	delete(s.items, key)
+}
+
+// Compute 샤드 lock 을 잡은 상태에서 fn 으로 새 값을 계산함. fn 이 keep=false 를 리턴하면 키를 지움
+// fn 안에서 같은 Map 을 호출하면 데드락이 생길 수 있음
+func (m *Map[K, V]) Compute(key K, fn func(old V, loaded bool) (value V, keep bool)) (V, bool) {
+	s := m.shard(key)
+	defer s.lock.Unlock()
+
+	s.lock.Lock()
+	old, loaded := s.items[key]
+	value, keep := fn(old, loaded)
+	if !keep {
+		delete(s.items, key)
+		var zero V
+		return zero, false
+	}
+
+	s.items[key] = value
+	return value, true
+}
+
+// Range 샤드별 스냅샷을 순회하므로 fn 안에서 Map 을 수정해도 됨. fn 이 false 를 리턴하면 중단
+func (m *Map[K, V]) Range(fn func(key K, value V) bool) {
+	type pair struct {
+		key   K
+		value V
+	}
+
+	for i := range m.shards {
+		s := &m.shards[i]
+
+		s.lock.RLock()
+		snapshot := make([]pair, 0, len(s.items))
+		for k, v := range s.items {
+			snapshot = append(snapshot, pair{key: k, value: v})
+		}
+		s.lock.RUnlock()
+
+		for _, p := range snapshot {
+			if !fn(p.key, p.value) {
+				return
+			}
+		}
+	}
+}
+
+func (m *Map[K, V]) Len() int {
+	result := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.lock.RLock()
+		result += len(s.items)
+		s.lock.RUnlock()
+	}
+
+	return result
+}
+
+func (m *Map[K, V]) Clear() {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.lock.Lock()
+		s.items = make(map[K]V)
+		s.lock.Unlock()
+	}
+}
@@ -0,0 +1,285 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrPoolClosed = errors.New("container: pool is closed")
+)
+
+// PoolOptions Factory 는 필수. Validate 는 Acquire 시 idle 아이템을 꺼낼 때 호출되며 idle 은 반납 후 지난 시간
+// MaxTotal 이 0 이면 개수 제한 없음. MaxIdleTime 이 0 보다 크면 EvictInterval 마다 오래 쉰 아이템을 MinIdle 까지 정리함
+type PoolOptions[T any] struct {
+	Factory       func(ctx context.Context) (T, error)
+	Validate      func(item T, idle time.Duration) bool
+	Destroy       func(item T)
+	MinIdle       int
+	MaxTotal      int
+	MaxIdleTime   time.Duration
+	EvictInterval time.Duration
+}
+
+type PoolStats struct {
+	Total     int
+	Idle      int
+	InUse     int
+	Created   uint64
+	Destroyed uint64
+	Acquired  uint64
+	Waited    uint64
+	Timeouts  uint64
+}
+
+type pooledItem[T any] struct {
+	item  T
+	since time.Time
+}
+
+// Pool 범용 오브젝트 풀. redis 핸들러, rpc 커넥션, 바이트 버퍼 등에 사용
+type Pool[T any] struct {
+	options  PoolOptions[T]
+	lock     sync.Mutex
+	idle     []pooledItem[T]
+	total    int
+	closed   bool
+	released signal
+	stats    PoolStats
+	stop     chan struct{}
+}
+
+// NewPool MinIdle 만큼 미리 만들어 둠. 만드는 중 실패하면 이미 만든 아이템을 정리하고 에러를 리턴
+func NewPool[T any](options PoolOptions[T]) (*Pool[T], error) {
+	if options.Factory == nil {
+		return nil, errors.New("container: pool factory is nil")
+	}
+
+	result := new(Pool[T])
+	result.options = options
+	result.stop = make(chan struct{})
+
+	if err := result.fill(context.Background()); err != nil {
+		result.Close()
+		return nil, err
+	}
+
+	if options.MaxIdleTime > 0 {
+		interval := options.EvictInterval
+		if interval <= 0 {
+			interval = options.MaxIdleTime / 2
+		}
+		go result.evictLoop(interval)
+	}
+
+	return result, nil
+}
+
+// Acquire 쉬는 아이템이 없고 MaxTotal 에 도달했으면 반납될 때까지 ctx 만큼 대기
+func (p *Pool[T]) Acquire(ctx context.Context) (T, error) {
+	var zero T
+
+	p.lock.Lock()
+	waited := false
+	for {
+		if p.closed {
+			p.lock.Unlock()
+			return zero, ErrPoolClosed
+		}
+
+		if n := len(p.idle); n > 0 {
+			pooled := p.idle[n-1]
+			p.idle[n-1] = pooledItem[T]{}
+			p.idle = p.idle[:n-1]
+			p.lock.Unlock()
+
+			if p.options.Validate == nil || p.options.Validate(pooled.item, time.Since(pooled.since)) {
+				p.lock.Lock()
+				p.stats.Acquired++
+				p.lock.Unlock()
+				return pooled.item, nil
+			}
+
+			p.destroy(pooled.item)
+			p.lock.Lock()
+			continue
+		}
+
+		if p.options.MaxTotal <= 0 || p.total < p.options.MaxTotal {
+			p.total++
+			p.lock.Unlock()
+
+			item, err := p.options.Factory(ctx)
+
+			p.lock.Lock()
+			if err != nil {
+				p.total--
+				p.released.broadcast()
+				p.lock.Unlock()
+				return zero, err
+			}
+			p.stats.Created++
+			p.stats.Acquired++
+			p.lock.Unlock()
+			return item, nil
+		}
+
+		if !waited {
+			waited = true
+			p.stats.Waited++
+		}
+
+		wait := p.released.wait()
+		p.lock.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			p.lock.Lock()
+			p.stats.Timeouts++
+			p.lock.Unlock()
+			return zero, ctx.Err()
+		}
+
+		p.lock.Lock()
+	}
+}
+
+func (p *Pool[T]) AcquireTimeout(timeout time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return p.Acquire(ctx)
+}
+
+// Release 다 쓴 아이템을 반납. 닫힌 풀이면 바로 파기함
+func (p *Pool[T]) Release(item T) {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		p.destroy(item)
+		return
+	}
+
+	p.idle = append(p.idle, pooledItem[T]{item: item, since: time.Now()})
+	p.released.broadcast()
+	p.lock.Unlock()
+}
+
+// Discard 망가진 아이템을 반납하지 않고 파기
+func (p *Pool[T]) Discard(item T) {
+	p.destroy(item)
+}
+
+func (p *Pool[T]) Stats() PoolStats {
+	defer p.lock.Unlock()
+
+	p.lock.Lock()
+	result := p.stats
+	result.Total = p.total
+	result.Idle = len(p.idle)
+	result.InUse = p.total - len(p.idle)
+
+	return result
+}
+
+// Close 쉬는 아이템을 모두 파기. 사용중인 아이템은 Release 될 때 파기됨
+func (p *Pool[T]) Close() {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return
+	}
+
+	p.closed = true
+	idle := p.idle
+	p.idle = nil
+	close(p.stop)
+	p.released.broadcast()
+	p.lock.Unlock()
+
+	for _, pooled := range idle {
+		p.destroy(pooled.item)
+	}
+}
+
+func (p *Pool[T]) destroy(item T) {
+	if p.options.Destroy != nil {
+		p.options.Destroy(item)
+	}
+
+	p.lock.Lock()
+	p.total--
+	p.stats.Destroyed++
+	p.released.broadcast()
+	p.lock.Unlock()
+}
+
+// fill 쉬는 아이템을 MinIdle 까지 채움
+func (p *Pool[T]) fill(ctx context.Context) error {
+	for {
+		p.lock.Lock()
+		if p.closed || len(p.idle) >= p.options.MinIdle || (p.options.MaxTotal > 0 && p.total >= p.options.MaxTotal) {
+			p.lock.Unlock()
+			return nil
+		}
+		p.total++
+		p.lock.Unlock()
+
+		item, err := p.options.Factory(ctx)
+
+		p.lock.Lock()
+		if err != nil {
+			p.total--
+			p.lock.Unlock()
+			return err
+		}
+		p.stats.Created++
+		p.idle = append(p.idle, pooledItem[T]{item: item, since: time.Now()})
+		p.released.broadcast()
+		p.lock.Unlock()
+	}
+}
+
+func (p *Pool[T]) evictLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.evictIdle()
+			_ = p.fill(context.Background())
+		}
+	}
+}
+
+// evictIdle 오래 쉰 아이템부터 MinIdle 을 남기고 파기
+func (p *Pool[T]) evictIdle() {
+	var expired []T
+
+	p.lock.Lock()
+	now := time.Now()
+	kept := p.idle[:0]
+	for i, pooled := range p.idle {
+		remain := len(p.idle) - i - 1 + len(kept)
+		if now.Sub(pooled.since) > p.options.MaxIdleTime && remain >= p.options.MinIdle {
+			expired = append(expired, pooled.item)
+			continue
+		}
+		kept = append(kept, pooled)
+	}
+	for i := len(kept); i < len(p.idle); i++ {
+		p.idle[i] = pooledItem[T]{}
+	}
+	p.idle = kept
+	p.lock.Unlock()
+
+	for _, item := range expired {
+		p.destroy(item)
+	}
+}
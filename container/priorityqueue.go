@@ -0,0 +1,182 @@
+package container
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+// indexedHeap container/heap 어댑터. 아이템이 자기 위치를 알고 있어야 Update, Remove 가 O(log n) 이 됨
+type indexedHeap[E any] struct {
+	items    []E
+	less     func(a, b E) bool
+	setIndex func(item E, index int)
+}
+
+func (h *indexedHeap[E]) Len() int {
+	return len(h.items)
+}
+
+func (h *indexedHeap[E]) Less(i, j int) bool {
+	return h.less(h.items[i], h.items[j])
+}
+
+func (h *indexedHeap[E]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.setIndex(h.items[i], i)
+	h.setIndex(h.items[j], j)
+}
+
+func (h *indexedHeap[E]) Push(x any) {
+	item := x.(E)
+	h.setIndex(item, len(h.items))
+	h.items = append(h.items, item)
+}
+
+func (h *indexedHeap[E]) Pop() any {
+	var zero E
+
+	n := len(h.items) - 1
+	item := h.items[n]
+	h.items[n] = zero
+	h.items = h.items[:n]
+	h.setIndex(item, -1)
+
+	return item
+}
+
+// PriorityItem PriorityQueue.Push 가 돌려주는 핸들. Update, Remove 에 사용
+type PriorityItem[T any] struct {
+	Value T
+	index int
+}
+
+// PriorityQueue less(a, b) 가 true 이면 a 가 먼저 나오는 동시성 우선순위 큐
+type PriorityQueue[T any] struct {
+	lock     sync.Mutex
+	heap     indexedHeap[*PriorityItem[T]]
+	notEmpty signal
+	closed   bool
+}
+
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	result := new(PriorityQueue[T])
+	result.heap.less = func(a, b *PriorityItem[T]) bool {
+		return less(a.Value, b.Value)
+	}
+	result.heap.setIndex = func(item *PriorityItem[T], index int) {
+		item.index = index
+	}
+
+	return result
+}
+
+func (q *PriorityQueue[T]) Push(value T) *PriorityItem[T] {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	item := &PriorityItem[T]{Value: value}
+	heap.Push(&q.heap, item)
+	q.notEmpty.broadcast()
+
+	return item
+}
+
+// Update 값을 바꾸고 우선순위를 다시 계산. 이미 꺼내졌거나 지워진 아이템이면 false
+func (q *PriorityQueue[T]) Update(item *PriorityItem[T], value T) bool {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	if !q.contains(item) {
+		return false
+	}
+
+	item.Value = value
+	heap.Fix(&q.heap, item.index)
+	return true
+}
+
+func (q *PriorityQueue[T]) Remove(item *PriorityItem[T]) bool {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	if !q.contains(item) {
+		return false
+	}
+
+	heap.Remove(&q.heap, item.index)
+	return true
+}
+
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	if q.heap.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return q.heap.items[0].Value, true
+}
+
+func (q *PriorityQueue[T]) TryPop() (T, bool) {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	if q.heap.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+
+	return heap.Pop(&q.heap).(*PriorityItem[T]).Value, true
+}
+
+// PopWait 아이템이 들어올 때까지 대기. 닫힌 큐가 비었으면 ErrQueueClosed
+func (q *PriorityQueue[T]) PopWait(ctx context.Context) (T, error) {
+	var zero T
+
+	q.lock.Lock()
+	for q.heap.Len() == 0 {
+		if q.closed {
+			q.lock.Unlock()
+			return zero, ErrQueueClosed
+		}
+
+		wait := q.notEmpty.wait()
+		q.lock.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+
+		q.lock.Lock()
+	}
+
+	result := heap.Pop(&q.heap).(*PriorityItem[T]).Value
+	q.lock.Unlock()
+
+	return result, nil
+}
+
+func (q *PriorityQueue[T]) Len() int {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	return q.heap.Len()
+}
+
+// Close 대기중인 PopWait 를 모두 깨움
+func (q *PriorityQueue[T]) Close() {
+	defer q.lock.Unlock()
+
+	q.lock.Lock()
+	q.closed = true
+	q.notEmpty.broadcast()
+}
+
+func (q *PriorityQueue[T]) contains(item *PriorityItem[T]) bool {
+	return item != nil && item.index >= 0 && item.index < q.heap.Len() && q.heap.items[item.index] == item
+}
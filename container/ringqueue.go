@@ -0,0 +1,109 @@
+package container
+
+import "sync/atomic"
+
+const cacheLineSize = 64
+
+type ringCell[T any] struct {
+	sequence atomic.Uint64
+	data     T
+}
+
+// RingQueue 고정 용량의 lock-free MPMC 링 버퍼 (Dmitry Vyukov 의 bounded MPMC queue)
+// 용량은 2 의 거듭제곱으로 올림. 가득 차면 Push 가 false, 비었으면 Pop 이 false 를 리턴하며 대기하지 않음
+type RingQueue[T any] struct {
+	_          [cacheLineSize]byte
+	enqueuePos atomic.Uint64
+	_          [cacheLineSize - 8]byte
+	dequeuePos atomic.Uint64
+	_          [cacheLineSize - 8]byte
+	mask       uint64
+	cells      []ringCell[T]
+}
+
+func NewRingQueue[T any](capacity int) *RingQueue[T] {
+	size := 2
+	for size < capacity {
+		size <<= 1
+	}
+
+	result := new(RingQueue[T])
+	result.mask = uint64(size - 1)
+	result.cells = make([]ringCell[T], size)
+	for i := range result.cells {
+		result.cells[i].sequence.Store(uint64(i))
+	}
+
+	return result
+}
+
+func (q *RingQueue[T]) Push(item T) bool {
+	var cell *ringCell[T]
+
+	pos := q.enqueuePos.Load()
+	for {
+		cell = &q.cells[pos&q.mask]
+		seq := cell.sequence.Load()
+		diff := int64(seq) - int64(pos)
+
+		if diff == 0 {
+			if q.enqueuePos.CompareAndSwap(pos, pos+1) {
+				break
+			}
+			pos = q.enqueuePos.Load()
+		} else if diff < 0 {
+			return false
+		} else {
+			pos = q.enqueuePos.Load()
+		}
+	}
+
+	cell.data = item
+	cell.sequence.Store(pos + 1)
+	return true
+}
+
+func (q *RingQueue[T]) Pop() (T, bool) {
+	var (
+		cell *ringCell[T]
+		zero T
+	)
+
+	pos := q.dequeuePos.Load()
+	for {
+		cell = &q.cells[pos&q.mask]
+		seq := cell.sequence.Load()
+		diff := int64(seq) - int64(pos+1)
+
+		if diff == 0 {
+			if q.dequeuePos.CompareAndSwap(pos, pos+1) {
+				break
+			}
+			pos = q.dequeuePos.Load()
+		} else if diff < 0 {
+			return zero, false
+		} else {
+			pos = q.dequeuePos.Load()
+		}
+	}
+
+	result := cell.data
+	cell.data = zero
+	cell.sequence.Store(pos + q.mask + 1)
+	return result, true
+}
+
+// Len 동시에 Push, Pop 이 일어나는 중에는 근사값
+func (q *RingQueue[T]) Len() int {
+	enqueue := q.enqueuePos.Load()
+	dequeue := q.dequeuePos.Load()
+	if enqueue < dequeue {
+		return 0
+	}
+
+	return int(enqueue - dequeue)
+}
+
+func (q *RingQueue[T]) Cap() int {
+	return len(q.cells)
+}
@@ -0,0 +1,200 @@
+package container
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type benchQueue interface {
+	Push(item int)
+	Pop() (int, bool)
+}
+
+// ringBenchQueue Push 가 실패하면 자리가 날 때까지 재시도해서 SafeQueue 와 같은 조건으로 비교함
+type ringBenchQueue struct {
+	queue *RingQueue[int]
+}
+
+func (r ringBenchQueue) Push(item int) {
+	for !r.queue.Push(item) {
+		runtime.Gosched()
+	}
+}
+
+func (r ringBenchQueue) Pop() (int, bool) {
+	return r.queue.Pop()
+}
+
+func TestRingQueueCapacity(t *testing.T) {
+	cases := map[int]int{0: 2, 1: 2, 2: 2, 3: 4, 1000: 1024, 1024: 1024}
+	for capacity, expected := range cases {
+		if got := NewRingQueue[int](capacity).Cap(); got != expected {
+			t.Errorf("NewRingQueue(%d).Cap() = %d, want %d", capacity, got, expected)
+		}
+	}
+}
+
+// TestRingQueueSequential 한 goroutine 에서 무작위 Push, Pop 결과를 슬라이스 모델과 비교함
+func TestRingQueueSequential(t *testing.T) {
+	q := NewRingQueue[int](8)
+	var model []int
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 100000; i++ {
+		if rng.IntN(2) == 0 {
+			ok := q.Push(i)
+			if ok != (len(model) < q.Cap()) {
+				t.Fatalf("step %d: Push = %v with %d items", i, ok, len(model))
+			}
+			if ok {
+				model = append(model, i)
+			}
+		} else {
+			v, ok := q.Pop()
+			if ok != (len(model) > 0) {
+				t.Fatalf("step %d: Pop ok = %v with %d items", i, ok, len(model))
+			}
+			if ok {
+				if v != model[0] {
+					t.Fatalf("step %d: Pop = %d, want %d", i, v, model[0])
+				}
+				model = model[1:]
+			}
+		}
+
+		if q.Len() != len(model) {
+			t.Fatalf("step %d: Len = %d, want %d", i, q.Len(), len(model))
+		}
+	}
+}
+
+// TestRingQueueConcurrent 여러 producer, consumer 가 동시에 쓸 때 모든 값이 정확히 한번 나오고
+// 각 consumer 가 본 같은 producer 의 값은 넣은 순서대로인지 확인함 (-race 와 함께 실행)
+func TestRingQueueConcurrent(t *testing.T) {
+	const (
+		producers = 8
+		consumers = 8
+	)
+
+	perProducer := 20000
+	if testing.Short() {
+		perProducer = 2000
+	}
+
+	q := NewRingQueue[int](64)
+	total := producers * perProducer
+	seen := make([]atomic.Int32, total)
+	var popped atomic.Int64
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				for !q.Push(p*perProducer + i) {
+					runtime.Gosched()
+				}
+			}
+		}(p)
+	}
+
+	errs := make(chan error, consumers)
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			last := make([]int, producers)
+			for i := range last {
+				last[i] = -1
+			}
+
+			for popped.Load() < int64(total) {
+				v, ok := q.Pop()
+				if !ok {
+					runtime.Gosched()
+					continue
+				}
+				popped.Add(1)
+
+				if seen[v].Add(1) != 1 {
+					errs <- fmt.Errorf("value %d popped more than once", v)
+					return
+				}
+
+				p, seq := v/perProducer, v%perProducer
+				if seq <= last[p] {
+					errs <- fmt.Errorf("producer %d: popped %d after %d", p, seq, last[p])
+					return
+				}
+				last[p] = seq
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	for v := range seen {
+		if seen[v].Load() != 1 {
+			t.Fatalf("value %d popped %d times", v, seen[v].Load())
+		}
+	}
+	if q.Len() != 0 {
+		t.Fatalf("Len = %d after draining", q.Len())
+	}
+}
+
+// benchmarkQueue goroutines 개의 goroutine 이 b.N 번의 Push, Pop 쌍을 나눠서 실행함
+func benchmarkQueue(b *testing.B, newQueue func() benchQueue) {
+	for _, goroutines := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("goroutines=%d", goroutines), func(b *testing.B) {
+			q := newQueue()
+
+			var wg sync.WaitGroup
+			b.ReportAllocs()
+			b.ResetTimer()
+			for g := 0; g < goroutines; g++ {
+				n := b.N / goroutines
+				if g < b.N%goroutines {
+					n++
+				}
+
+				wg.Add(1)
+				go func(n int) {
+					defer wg.Done()
+					for i := 0; i < n; i++ {
+						q.Push(i)
+						for {
+							if _, ok := q.Pop(); ok {
+								break
+							}
+							runtime.Gosched()
+						}
+					}
+				}(n)
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func BenchmarkRingQueue(b *testing.B) {
+	benchmarkQueue(b, func() benchQueue {
+		return ringBenchQueue{queue: NewRingQueue[int](1024)}
+	})
+}
+
+func BenchmarkSafeQueue(b *testing.B) {
+	benchmarkQueue(b, func() benchQueue {
+		return new(SafeQueue[int])
+	})
+}
@@ -0,0 +1,54 @@
+package container
+
+// Set Map 위에 얹은 동시성 집합
+type Set[T comparable] struct {
+	items *Map[T, struct{}]
+}
+
+func NewSet[T comparable](shardCount int) *Set[T] {
+	result := new(Set[T])
+	result.items = NewMap[T, struct{}](shardCount)
+
+	return result
+}
+
+// Add 새로 추가됐으면 true
+func (s *Set[T]) Add(item T) bool {
+	_, loaded := s.items.LoadOrStore(item, struct{}{})
+	return !loaded
+}
+
+// Remove 있던 아이템을 지웠으면 true
+func (s *Set[T]) Remove(item T) bool {
+	_, loaded := s.items.LoadAndDelete(item)
+	return loaded
+}
+
+func (s *Set[T]) Contains(item T) bool {
+	_, ok := s.items.Load(item)
+	return ok
+}
+
+func (s *Set[T]) Range(fn func(item T) bool) {
+	s.items.Range(func(key T, _ struct{}) bool {
+		return fn(key)
+	})
+}
+
+func (s *Set[T]) Items() []T {
+	result := make([]T, 0, s.items.Len())
+	s.items.Range(func(key T, _ struct{}) bool {
+		result = append(result, key)
+		return true
+	})
+
+	return result
+}
+
+func (s *Set[T]) Len() int {
+	return s.items.Len()
+}
+
+func (s *Set[T]) Clear() {
+	s.items.Clear()
+}
@@ -0,0 +1,21 @@
+package container
+
+// signal 대기중인 고루틴 전부를 깨우기 위한 채널. sync.Cond 와 달리 context 와 함께 select 할 수 있음
+// wait 와 broadcast 는 소유자의 lock 을 잡은 상태에서 호출해야 함
+type signal struct {
+	ch chan struct{}
+}
+
+func (s *signal) wait() <-chan struct{} {
+	if s.ch == nil {
+		s.ch = make(chan struct{})
+	}
+	return s.ch
+}
+
+func (s *signal) broadcast() {
+	if s.ch != nil {
+		close(s.ch)
+		s.ch = nil
+	}
+}
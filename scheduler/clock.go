@@ -0,0 +1,250 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 스케쥴러와 xlog 가 사용하는 시계. 테스트에서는 FakeClock 으로 바꿔 끼움
+// AfterFunc 는 클러스터 싱글톤 잡의 락 연장, 해제 타이머에 사용됨
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer Clock.AfterFunc 가 돌려주는 타이머. Stop 은 f 가 아직 실행되지 않았으면 true
+type Timer interface {
+	Stop() bool
+}
+
+type systemClock struct{}
+
+// SystemClock 실제 시간을 사용하는 기본 시계
+var SystemClock Clock = systemClock{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+/*
+## 사용법 ##
+	clock := scheduler.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	handler := new(scheduler.Handler)
+	handler.SetClock(clock)
+	handler.Run(scheduler.PriorityNormal)		// 가짜 시계에서는 고루틴을 띄우지 않음
+
+	handler.NewObject(scheduler.CreateObjectByEveryDay(4, 0, 0, resetDaily))
+	clock.Advance(72 * time.Hour)				// 1/2, 1/3 04:00 에 resetDaily 가 이 호출 안에서 실행됨
+*/
+
+// FakeClock 수동으로 시간을 움직이는 시계. Advance 는 붙어있는 Handler 의 잡을 호출한 고루틴에서 실행함
+// AfterFunc 의 f 도 시간이 발생 시각을 지날 때 Advance, Sleep 을 호출한 고루틴에서 실행됨
+type FakeClock struct {
+	lock     sync.Mutex
+	advance  sync.Mutex
+	now      time.Time
+	handlers []*Handler
+	timers   []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	fn    func()
+}
+
+func NewFakeClock(start time.Time) *FakeClock {
+	result := new(FakeClock)
+	result.now = start
+
+	return result
+}
+
+func (f *FakeClock) Now() time.Time {
+	defer f.lock.Unlock()
+
+	f.lock.Lock()
+	return f.now
+}
+
+// Sleep 시간을 움직이면서 그 사이의 타이머는 실행하지만 잡은 실행하지 않음 (잡 안에서 호출되어도 재진입하지 않도록)
+func (f *FakeClock) Sleep(d time.Duration) {
+	target := f.Now().Add(d)
+	f.fireTimers(target)
+	f.set(target)
+}
+
+// AfterFunc d 가 0 이하이면 다음에 Advance 나 Sleep 을 호출할 때 실행됨
+func (f *FakeClock) AfterFunc(d time.Duration, fn func()) Timer {
+	defer f.lock.Unlock()
+
+	f.lock.Lock()
+	timer := &fakeTimer{clock: f, at: f.now.Add(d), fn: fn}
+	f.timers = append(f.timers, timer)
+
+	return timer
+}
+
+func (t *fakeTimer) Stop() bool {
+	defer t.clock.lock.Unlock()
+
+	t.clock.lock.Lock()
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// nextTimer 가장 먼저 실행될 타이머의 시각
+func (f *FakeClock) nextTimer() (time.Time, bool) {
+	defer f.lock.Unlock()
+
+	f.lock.Lock()
+	var (
+		result time.Time
+		found  bool
+	)
+	for _, timer := range f.timers {
+		if !found || timer.at.Before(result) {
+			result = timer.at
+			found = true
+		}
+	}
+
+	return result, found
+}
+
+// fireTimers until 까지의 타이머를 시각 순서대로 실행. f 안에서 새 타이머를 걸 수 있도록 lock 밖에서 호출함
+func (f *FakeClock) fireTimers(until time.Time) {
+	for {
+		f.lock.Lock()
+		index := -1
+		for i, timer := range f.timers {
+			if !timer.at.After(until) && (index < 0 || timer.at.Before(f.timers[index].at)) {
+				index = i
+			}
+		}
+		if index < 0 {
+			f.lock.Unlock()
+			return
+		}
+
+		timer := f.timers[index]
+		f.timers = append(f.timers[:index], f.timers[index+1:]...)
+		if timer.at.After(f.now) {
+			f.now = timer.at
+		}
+		f.lock.Unlock()
+
+		timer.fn()
+	}
+}
+
+// Advance d 만큼 시간을 움직이면서 그 사이에 발생 시각이 된 잡과 타이머를 시간 순서대로 모두 실행함
+func (f *FakeClock) Advance(d time.Duration) {
+	f.AdvanceTo(f.Now().Add(d))
+}
+
+func (f *FakeClock) AdvanceTo(target time.Time) {
+	// 0 간격 잡처럼 시간이 흐르지 않는데도 계속 발생 시각이 되는 잡에서 빠져나오기 위한 제한
+	const maxFiresAtSameTime = 10000
+
+	defer f.advance.Unlock()
+
+	f.advance.Lock()
+	var (
+		last  time.Time
+		fires int
+	)
+
+	for {
+		f.lock.Lock()
+		handlers := append([]*Handler(nil), f.handlers...)
+		f.lock.Unlock()
+
+		var (
+			next  time.Time
+			found bool
+		)
+		for _, h := range handlers {
+			h.activateObject()
+			if due, ok := h.nextDue(); ok && (!found || due.Before(next)) {
+				next = due
+				found = true
+			}
+		}
+
+		fireTimer := false
+		if at, ok := f.nextTimer(); ok && (!found || !at.After(next)) {
+			next = at
+			found = true
+			fireTimer = true
+		}
+
+		if !found || next.After(target) {
+			break
+		}
+
+		if next.Equal(last) {
+			fires++
+			if fires >= maxFiresAtSameTime {
+				break
+			}
+		} else {
+			last = next
+			fires = 0
+		}
+
+		if fireTimer {
+			f.fireTimers(next)
+			continue
+		}
+
+		f.set(next)
+		for _, h := range handlers {
+			h.procOnce()
+		}
+	}
+
+	f.set(target)
+}
+
+func (f *FakeClock) set(t time.Time) {
+	f.lock.Lock()
+	if t.After(f.now) {
+		f.now = t
+	}
+	f.lock.Unlock()
+}
+
+func (f *FakeClock) attach(h *Handler) {
+	defer f.lock.Unlock()
+
+	f.lock.Lock()
+	f.handlers = append(f.handlers, h)
+}
+
+func (f *FakeClock) detach(h *Handler) {
+	defer f.lock.Unlock()
+
+	f.lock.Lock()
+	for i, handler := range f.handlers {
+		if handler == h {
+			f.handlers = append(f.handlers[:i], f.handlers[i+1:]...)
+			return
+		}
+	}
+}
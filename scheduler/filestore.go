@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// FileJobStore 잡을 json 파일 하나에 보관하는 저장소. 기록은 임시 파일에 쓴 뒤 rename 으로 교체함
+type FileJobStore struct {
+	path    string
+	lock    sync.Mutex
+	records map[string]JobRecord
+}
+
+func NewFileJobStore(path string) (*FileJobStore, error) {
+	result := new(FileJobStore)
+	result.path = path
+	result.records = make(map[string]JobRecord)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, err
+	}
+
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &result.records); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (f *FileJobStore) Save(record JobRecord) error {
+	defer f.lock.Unlock()
+
+	f.lock.Lock()
+	f.records[record.Name] = record
+	return f.flush()
+}
+
+func (f *FileJobStore) Delete(name string) error {
+	defer f.lock.Unlock()
+
+	f.lock.Lock()
+	if _, ok := f.records[name]; !ok {
+		return nil
+	}
+
+	delete(f.records, name)
+	return f.flush()
+}
+
+func (f *FileJobStore) Load() ([]JobRecord, error) {
+	defer f.lock.Unlock()
+
+	f.lock.Lock()
+	result := make([]JobRecord, 0, len(f.records))
+	for _, record := range f.records {
+		result = append(result, record)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].NextFire.Before(result[j].NextFire)
+	})
+
+	return result, nil
+}
+
+func (f *FileJobStore) flush() error {
+	b, err := json.MarshalIndent(f.records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(f.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	tmpPath := f.path + ".tmp"
+	if err := os.WriteFile(tmpPath, b, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, f.path)
+}
@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+var jobsTemplate = template.Must(template.New("jobs").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Scheduler jobs</title></head>
+<body>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Name</th><th>Type</th><th>Paused</th><th>Next fire</th><th>Last run</th><th>Last duration</th><th>Run count</th><th>Last error</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.Paused}}</td><td>{{.NextFire.Format "2006-01-02 15:04:05 MST"}}</td><td>{{if .LastRun.IsZero}}-{{else}}{{.LastRun.Format "2006-01-02 15:04:05 MST"}}{{end}}</td><td>{{.LastDuration}}</td><td>{{.RunCount}}</td><td>{{.LastError}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// HTTPHandler 잡 상태를 보여주는 http.Handler. 브라우저(Accept: text/html)는 테이블로, 그 외에는 JSON 으로 응답
+// gin 에서는 gin.WrapH(handler.HTTPHandler()) 로 붙이면 됨
+func (s *Handler) HTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		jobs := s.Jobs()
+		if strings.Contains(r.Header.Get("Accept"), "text/html") {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_ = jobsTemplate.Execute(w, jobs)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jobs)
+	})
+}
@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrLockNotAcquired = errors.New("scheduler: lock is held by another owner")
+	ErrLockLost        = errors.New("scheduler: lock lease was lost")
+)
+
+// Locker 여러 레플리카 사이에서 잡을 한 곳에서만 실행하기 위한 분산 락
+// 구현체: MemoryLocker (테스트용), xredis.Locker, xmysql.Locker
+type Locker interface {
+	// Acquire 다른 소유자가 잡고 있으면 ErrLockNotAcquired 를 리턴
+	Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error)
+}
+
+// Lease 획득한 락. Renew 는 ttl 을 연장하고 Release 는 락을 해제함
+type Lease interface {
+	Renew(ctx context.Context, ttl time.Duration) error
+	Release(ctx context.Context) error
+}
+
+type memoryEntry struct {
+	token  string
+	expire time.Time
+}
+
+// MemoryLocker 프로세스 내부 락. 하나의 인스턴스를 여러 Handler 가 공유하면 레플리카를 흉내낼 수 있음
+type MemoryLocker struct {
+	lock    sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryLease struct {
+	locker *MemoryLocker
+	key    string
+	token  string
+}
+
+func NewMemoryLocker() *MemoryLocker {
+	result := new(MemoryLocker)
+	result.entries = make(map[string]memoryEntry)
+
+	return result
+}
+
+func (m *MemoryLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error) {
+	defer m.lock.Unlock()
+
+	m.lock.Lock()
+	now := time.Now()
+	for k, entry := range m.entries {
+		if !now.Before(entry.expire) {
+			delete(m.entries, k)
+		}
+	}
+
+	if _, ok := m.entries[key]; ok {
+		return nil, ErrLockNotAcquired
+	}
+
+	token := NewLockToken()
+	m.entries[key] = memoryEntry{token: token, expire: now.Add(ttl)}
+
+	return &memoryLease{locker: m, key: key, token: token}, nil
+}
+
+func (l *memoryLease) Renew(ctx context.Context, ttl time.Duration) error {
+	defer l.locker.lock.Unlock()
+
+	l.locker.lock.Lock()
+	entry, ok := l.locker.entries[l.key]
+	if !ok || entry.token != l.token || time.Now().After(entry.expire) {
+		return ErrLockLost
+	}
+
+	entry.expire = time.Now().Add(ttl)
+	l.locker.entries[l.key] = entry
+	return nil
+}
+
+func (l *memoryLease) Release(ctx context.Context) error {
+	defer l.locker.lock.Unlock()
+
+	l.locker.lock.Lock()
+	if entry, ok := l.locker.entries[l.key]; ok && entry.token == l.token {
+		delete(l.locker.entries, l.key)
+	}
+	return nil
+}
+
+// NewLockToken 락 소유자를 구분하기 위한 랜덤 토큰
+func NewLockToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,33 @@
+package scheduler
+
+import "time"
+
+// RetryPolicy 잡 실패 시 재시도 정책
+// MaxRetries 가 0 이면 재시도하지 않음. Multiplier 가 1 이하이면 2 로 간주
+type RetryPolicy struct {
+	MaxRetries int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	Multiplier float64
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 1 {
+		multiplier = 2
+	}
+
+	delay := float64(p.Backoff)
+	for i := 1; i < attempt; i++ {
+		delay *= multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+
+	return time.Duration(delay)
+}
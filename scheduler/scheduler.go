@@ -1,7 +1,11 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/newbiediver/golib/exception"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -22,32 +26,80 @@ const (
 const (
 	intervalType procType = 0 + iota
 	everyDayType
+	onceType
 )
 
 const (
 	milliSecondToNanoSecond int64 = 1000000
+	// lockRetryInterval 락 백엔드 장애로 싱글톤 잡의 락을 확인하지 못했을 때 같은 발생 시각으로 다시 시도하는 간격
+	lockRetryInterval = time.Second
 )
 
 type Object struct {
 	key          uint64
+	name         string
 	interval     int64
 	lastTickTime int64
 	nextEvent    time.Time
 	objType      procType
-	completion   func()
-	stopFlag     bool
+	completion   func(ctx context.Context) error
+	timeout      time.Duration
+	retry        RetryPolicy
+	retryCount   int
+	retryAt      time.Time
+	singletonTTL time.Duration
+	lockRetryAt  time.Time
+	catchUp      int
+	record       *JobRecord
+	restored     bool
+	stopFlag     atomic.Bool
+	paused       atomic.Bool
+	triggered    atomic.Bool
+	lock         sync.Mutex
+	lastRun      time.Time
+	lastDuration time.Duration
+	runCount     uint64
+	lastError    string
 }
 
+// JobInfo 잡 상태 조회용 스냅샷
+type JobInfo struct {
+	Name         string        `json:"name"`
+	Type         string        `json:"type"`
+	Paused       bool          `json:"paused"`
+	NextFire     time.Time     `json:"nextFire"`
+	LastRun      time.Time     `json:"lastRun"`
+	LastDuration time.Duration `json:"lastDuration"`
+	RunCount     uint64        `json:"runCount"`
+	LastError    string        `json:"lastError,omitempty"`
+}
+
+// ErrorHook 잡이 error 를 리턴하거나 panic 이 발생했을 때 호출됨. attempt 는 재시도 횟수 (최초 실행은 0)
+type ErrorHook func(name string, attempt int, err error)
+
 type Handler struct {
-	termination  bool
+	termination  atomic.Bool
 	running      bool
 	keyContainer uint64
 	waiter       sync.WaitGroup
 	lock         *sync.Mutex
 	newObj       []*Object
 	activeObj    map[uint64]*Object
+	ctx          context.Context
+	cancel       context.CancelFunc
+	errorHook    ErrorHook
+	locker       Locker
+	store        JobStore
+	misfire      MisfirePolicy
+	jobFuncs     map[string]JobFunc
+	clock        Clock
+	fake         *FakeClock
 }
 
+var (
+	ErrJobNotFound = errors.New("scheduler: job not found")
+)
+
 var (
 	mainHandler  Handler
 	keptHandlers map[string]*Handler
@@ -78,6 +130,15 @@ func StopKeptHandlers() {
 }
 
 func CreateObjectByInterval(milliSecondInterval int64, completion func()) *Object {
+	return CreateObjectByIntervalContext(milliSecondInterval, wrapCompletion(completion))
+}
+
+func CreateObjectByEveryDay(hour int, minute int, second int, completion func()) *Object {
+	return CreateObjectByEveryDayContext(hour, minute, second, wrapCompletion(completion))
+}
+
+// CreateObjectByIntervalContext ctx 는 Handler.Stop 또는 잡 타임아웃 시 취소됨
+func CreateObjectByIntervalContext(milliSecondInterval int64, completion func(ctx context.Context) error) *Object {
 	toNanoSecondInterval := milliSecondInterval * milliSecondToNanoSecond
 	obj := new(Object)
 	obj.interval = toNanoSecondInterval
@@ -87,7 +148,7 @@ func CreateObjectByInterval(milliSecondInterval int64, completion func()) *Objec
 	return obj
 }
 
-func CreateObjectByEveryDay(hour int, minute int, second int, completion func()) *Object {
+func CreateObjectByEveryDayContext(hour int, minute int, second int, completion func(ctx context.Context) error) *Object {
 	tomorrow := time.Now().In(time.UTC).Add(24 * time.Hour)
 	obj := new(Object)
 	obj.nextEvent = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), hour, minute, second, 0, time.UTC)
@@ -96,31 +157,454 @@ func CreateObjectByEveryDay(hour int, minute int, second int, completion func())
 	return obj
 }
 
+func wrapCompletion(completion func()) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		completion()
+		return nil
+	}
+}
+
+func (t procType) String() string {
+	switch t {
+	case intervalType:
+		return "interval"
+	case everyDayType:
+		return "everyday"
+	case onceType:
+		return "once"
+	}
+	return "unknown"
+}
+
+// SetName 잡 이름 지정 (Jobs, Pause, Resume, TriggerNow 에서 사용)
+func (o *Object) SetName(name string) *Object {
+	o.name = name
+	return o
+}
+
+// SetTimeout 잡 1회 실행의 최대 시간. 넘어가면 ctx 가 취소됨
+func (o *Object) SetTimeout(timeout time.Duration) *Object {
+	o.timeout = timeout
+	return o
+}
+
+// SetRetryPolicy 실패 시 재시도 정책. 재시도는 스케쥴러 루프를 막지 않고 다음 틱 이후로 예약됨
+func (o *Object) SetRetryPolicy(policy RetryPolicy) *Object {
+	o.retry = policy
+	return o
+}
+
+// SetClusterSingleton 레플리카가 여러개여도 발생 시각마다 한 곳에서만 실행되도록 함 (Handler.SetLocker 필요)
+// 락 키는 잡 이름으로 만들어지므로 레플리카 간에 같은 이름을 지정해야 하고, 이름이 없으면 NewObject 에서 panic. ttl 은 레플리카 간 시계 오차보다 커야 함
+// interval 잡은 interval 단위로 잘린 구간마다 한번 실행됨. TriggerNow 와 재시도는 락 없이 해당 레플리카에서 실행됨
+func (o *Object) SetClusterSingleton(ttl time.Duration) *Object {
+	o.singletonTTL = ttl
+	return o
+}
+
+func (o *Object) Name() string {
+	return o.name
+}
+
+func (o *Object) Pause() {
+	o.paused.Store(true)
+}
+
+func (o *Object) Resume() {
+	o.paused.Store(false)
+}
+
+// TriggerNow 다음 틱에 스케쥴과 무관하게 한번 실행
+func (o *Object) TriggerNow() {
+	o.triggered.Store(true)
+}
+
+func (o *Object) info() JobInfo {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	result := JobInfo{
+		Name:         o.name,
+		Type:         o.objType.String(),
+		Paused:       o.paused.Load(),
+		LastRun:      o.lastRun,
+		LastDuration: o.lastDuration,
+		RunCount:     o.runCount,
+		LastError:    o.lastError,
+	}
+
+	if o.objType == intervalType {
+		result.NextFire = time.Unix(0, o.lastTickTime).In(time.UTC)
+	} else {
+		result.NextFire = o.nextEvent
+	}
+
+	return result
+}
+
+func (o *Object) isDue(now time.Time) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if now.Before(o.lockRetryAt) {
+		return false
+	}
+	if o.objType == intervalType {
+		return now.UnixNano() >= o.lastTickTime
+	}
+	return now.UnixNano() >= o.nextEvent.UnixNano()
+}
+
+// deferFire 스케쥴은 그대로 두고 발생 확인만 at 까지 미룸
+func (o *Object) deferFire(at time.Time) {
+	o.lock.Lock()
+	o.lockRetryAt = at
+	o.lock.Unlock()
+}
+
+// fireTime 지금 발생할 스케쥴 시각. 레플리카 간 락 키로 사용됨
+func (o *Object) fireTime() time.Time {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.objType == intervalType {
+		return time.Unix(0, o.lastTickTime).In(time.UTC).Truncate(time.Duration(o.interval))
+	}
+	return o.nextEvent
+}
+
+func (o *Object) reschedule(now time.Time) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	// 재시작 중 놓친 스케쥴을 모두 실행하는 중이면 스케쥴을 옮기지 않음
+	if o.catchUp > 0 {
+		o.catchUp--
+		return
+	}
+
+	if o.objType == onceType {
+		return
+	}
+
+	if o.objType == intervalType {
+		o.lastTickTime = now.UnixNano() + o.interval
+	} else {
+		next := time.Date(now.Year(), now.Month(), now.Day(), o.nextEvent.Hour(), o.nextEvent.Minute(), o.nextEvent.Second(), 0, time.UTC)
+		if !next.After(now) {
+			next = next.Add(24 * time.Hour)
+		}
+		o.nextEvent = next
+	}
+}
+
+func (o *Object) resetRetry() {
+	o.lock.Lock()
+	o.retryCount = 0
+	o.retryAt = time.Time{}
+	o.lock.Unlock()
+}
+
+// anchor 생성 함수로 만든 잡의 첫 발생 시각을 Handler 의 시계 기준으로 다시 잡음
+func (o *Object) anchor(now time.Time) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.objType == intervalType {
+		o.lastTickTime = now.UnixNano() + o.interval
+	} else if o.objType == everyDayType {
+		tomorrow := now.Add(24 * time.Hour)
+		o.nextEvent = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), o.nextEvent.Hour(), o.nextEvent.Minute(), o.nextEvent.Second(), 0, time.UTC)
+	}
+}
+
+// nextDue 다음에 실행될 시각. 일시정지된 잡은 제외
+func (o *Object) nextDue() (time.Time, bool) {
+	if o.stopFlag.Load() || o.paused.Load() {
+		return time.Time{}, false
+	}
+
+	info := o.info()
+	o.lock.Lock()
+	retryAt := o.retryAt
+	lockRetryAt := o.lockRetryAt
+	o.lock.Unlock()
+
+	next := info.NextFire
+	if next.Before(lockRetryAt) {
+		next = lockRetryAt
+	}
+	if !retryAt.IsZero() && retryAt.Before(next) {
+		return retryAt, true
+	}
+	return next, true
+}
+
+func (o *Object) isRetryDue(now time.Time) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return !o.retryAt.IsZero() && !now.Before(o.retryAt)
+}
+
+// execute 잡 하나를 실행하고 통계를 남김. 잡의 panic 이 스케쥴러 전체를 멈추지 않도록 여기서 recover 함
+func (s *Handler) execute(obj *Object) error {
+	ctx := s.ctx
+	if obj.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, obj.timeout)
+		defer cancel()
+	}
+
+	begin := s.now()
+	var result error
+
+	func() {
+		defer func() {
+			if rcv := recover(); rcv != nil {
+				result = fmt.Errorf("panic: %v", rcv)
+				if ex := exception.GetExceptionHandler(); ex != nil {
+					ex.ExceptionCallbackFunctor()
+				}
+			}
+		}()
+
+		result = obj.completion(ctx)
+	}()
+
+	obj.lock.Lock()
+	obj.lastRun = begin.In(time.UTC)
+	obj.lastDuration = s.now().Sub(begin)
+	obj.runCount++
+	obj.lastError = ""
+	if result != nil {
+		obj.lastError = result.Error()
+	}
+	obj.lock.Unlock()
+
+	return result
+}
+
+// acquireFire 클러스터 싱글톤 잡이면 발생 시각 단위의 락을 잡음. 다른 레플리카가 가져갔으면 false
+// 락 백엔드 장애면 에러를 리턴하며, 이때는 다른 레플리카가 실행했는지 알 수 없으므로 발생을 건너뛰면 안됨
+func (s *Handler) acquireFire(obj *Object, fireTime time.Time) (Lease, bool, error) {
+	if obj.singletonTTL <= 0 || s.locker == nil {
+		return nil, true, nil
+	}
+
+	key := fmt.Sprintf("scheduler:%s:%d", obj.name, fireTime.Unix())
+	lease, err := s.locker.Acquire(s.ctx, key, obj.singletonTTL)
+	if err != nil {
+		if errors.Is(err, ErrLockNotAcquired) {
+			return nil, false, nil
+		}
+		if s.errorHook != nil {
+			s.errorHook(obj.name, 0, err)
+		}
+		return nil, false, err
+	}
+
+	return lease, true, nil
+}
+
+// recoverLease 락 연장, 해제는 스케쥴러 루프 밖의 고루틴에서 돌기 때문에 Locker 구현의 panic 이 프로세스를 죽이지 않게 막음
+func (s *Handler) recoverLease(obj *Object) {
+	if rcv := recover(); rcv != nil {
+		if s.errorHook != nil {
+			s.errorHook(obj.name, 0, fmt.Errorf("lease panic: %v", rcv))
+		}
+		if ex := exception.GetExceptionHandler(); ex != nil {
+			ex.ExceptionCallbackFunctor()
+		}
+	}
+}
+
+// executeLeased 실행 중에는 ttl/3 마다 락을 연장하고, 끝난 뒤에도 획득 시점부터 ttl 이 지날 때까지 락을 유지함
+// (늦게 깨어난 레플리카가 같은 발생 시각을 다시 실행하지 않도록)
+func (s *Handler) executeLeased(obj *Object, lease Lease) error {
+	if lease == nil {
+		return s.execute(obj)
+	}
+
+	var (
+		renewLock  sync.Mutex
+		renewTimer Timer
+		stopped    bool
+		renew      func()
+	)
+
+	// 연장은 ttl/3 마다 타이머로 다시 걸어서 FakeClock 에서도 결정적으로 동작함
+	acquired := s.now()
+	renew = func() {
+		defer s.recoverLease(obj)
+
+		renewLock.Lock()
+		defer renewLock.Unlock()
+
+		if stopped {
+			return
+		}
+
+		if err := lease.Renew(s.ctx, obj.singletonTTL); err != nil {
+			if s.errorHook != nil {
+				s.errorHook(obj.name, 0, err)
+			}
+		} else {
+			acquired = s.now()
+		}
+		renewTimer = s.afterFunc(obj.singletonTTL/3, renew)
+	}
+
+	renewLock.Lock()
+	renewTimer = s.afterFunc(obj.singletonTTL/3, renew)
+	renewLock.Unlock()
+
+	result := s.execute(obj)
+
+	renewLock.Lock()
+	stopped = true
+	renewTimer.Stop()
+	remain := obj.singletonTTL - s.now().Sub(acquired)
+	renewLock.Unlock()
+
+	release := func() {
+		defer s.recoverLease(obj)
+
+		ctx, cancel := context.WithTimeout(context.Background(), obj.singletonTTL)
+		defer cancel()
+
+		if err := lease.Release(ctx); err != nil && s.errorHook != nil {
+			s.errorHook(obj.name, 0, err)
+		}
+	}
+	if remain > 0 {
+		s.afterFunc(remain, release)
+	} else {
+		release()
+	}
+
+	return result
+}
+
+// handleResult 실패한 잡은 에러 훅에 넘기고 정책에 따라 재시도를 예약함
+func (s *Handler) handleResult(obj *Object, err error, now time.Time) {
+	obj.lock.Lock()
+	attempt := obj.retryCount
+	if err == nil || attempt >= obj.retry.MaxRetries || s.termination.Load() {
+		obj.retryCount = 0
+		obj.retryAt = time.Time{}
+	} else {
+		obj.retryCount++
+		obj.retryAt = now.Add(obj.retry.backoff(obj.retryCount))
+	}
+	obj.lock.Unlock()
+
+	if err != nil && s.errorHook != nil {
+		s.errorHook(obj.name, attempt, err)
+	}
+}
+
 func (s *Handler) Run(priority Priority) {
 	if s.running {
 		return
 	}
 
 	s.lock = new(sync.Mutex)
-	s.waiter.Add(1)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
 	s.running = true
 
+	if s.store != nil {
+		s.loadStore()
+	}
+
+	// 가짜 시계는 루프를 돌리지 않고 FakeClock.Advance 에서 동기적으로 잡을 실행함
+	if s.fake != nil {
+		s.fake.attach(s)
+		return
+	}
+
+	s.waiter.Add(1)
 	go s.procObjects(priority)
 }
 
 func (s *Handler) Stop() {
+	_ = s.StopContext(context.Background())
+}
+
+// StopContext 실행 중인 잡의 ctx 를 취소하고 ctx 가 끝날 때까지 잡의 종료를 기다림
+func (s *Handler) StopContext(ctx context.Context) error {
 	if !s.running {
-		return
+		return nil
+	}
+	if !s.termination.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	s.cancel()
+	if s.fake != nil {
+		s.fake.detach(s)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.waiter.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetClock 스케쥴러가 사용할 시계 지정 (기본은 시스템 시계). Run 전에 호출해야 함
+func (s *Handler) SetClock(clock Clock) {
+	s.clock = clock
+	s.fake, _ = clock.(*FakeClock)
+}
+
+func (s *Handler) now() time.Time {
+	if s.clock == nil {
+		return time.Now().In(time.UTC)
+	}
+	return s.clock.Now().In(time.UTC)
+}
+
+func (s *Handler) afterFunc(d time.Duration, f func()) Timer {
+	if s.clock == nil {
+		return time.AfterFunc(d, f)
 	}
-	if s.termination {
+	return s.clock.AfterFunc(d, f)
+}
+
+func (s *Handler) sleep(d time.Duration) {
+	if s.clock == nil {
+		time.Sleep(d)
 		return
 	}
+	s.clock.Sleep(d)
+}
+
+// SetLocker 클러스터 싱글톤 잡에 사용할 분산 락 지정
+func (s *Handler) SetLocker(locker Locker) {
+	s.locker = locker
+}
 
-	s.termination = true
-	s.waiter.Wait()
+// SetErrorHook 잡 실패 시 호출될 훅 지정
+func (s *Handler) SetErrorHook(hook ErrorHook) {
+	s.errorHook = hook
 }
 
 func (s *Handler) NewObject(obj *Object) {
+	if obj.singletonTTL > 0 && obj.name == "" {
+		panic("[Scheduler] cluster singleton job needs a name")
+	}
+	if !obj.restored {
+		obj.anchor(s.now())
+	}
+
 	defer s.lock.Unlock()
 
 	s.lock.Lock()
@@ -128,16 +612,97 @@ func (s *Handler) NewObject(obj *Object) {
 }
 
 func (s *Handler) DeleteObject(obj *Object) {
-	obj.stopFlag = true
+	obj.stopFlag.Store(true)
 }
 
-func (s *Handler) activateObject() {
-	if s.newObj == nil {
-		return
+// Jobs 등록된 모든 잡의 상태를 반환 (아직 활성화 전인 잡 포함)
+func (s *Handler) Jobs() []JobInfo {
+	if s.lock == nil {
+		return nil
 	}
 
+	s.lock.Lock()
 	defer s.lock.Unlock()
+
+	result := make([]JobInfo, 0, len(s.activeObj)+len(s.newObj))
+	for _, obj := range s.activeObj {
+		if !obj.stopFlag.Load() {
+			result = append(result, obj.info())
+		}
+	}
+	for _, obj := range s.newObj {
+		if !obj.stopFlag.Load() {
+			result = append(result, obj.info())
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// FindObject 이름으로 잡을 찾음. 같은 이름으로 교체되어 다음 tick 에 지워질 잡은 건너뜀
+func (s *Handler) FindObject(name string) *Object {
+	if s.lock == nil {
+		return nil
+	}
+
 	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, obj := range s.activeObj {
+		if obj.name == name && !obj.stopFlag.Load() {
+			return obj
+		}
+	}
+	for _, obj := range s.newObj {
+		if obj.name == name && !obj.stopFlag.Load() {
+			return obj
+		}
+	}
+
+	return nil
+}
+
+func (s *Handler) Pause(name string) error {
+	obj := s.FindObject(name)
+	if obj == nil {
+		return ErrJobNotFound
+	}
+
+	obj.Pause()
+	return nil
+}
+
+func (s *Handler) Resume(name string) error {
+	obj := s.FindObject(name)
+	if obj == nil {
+		return ErrJobNotFound
+	}
+
+	obj.Resume()
+	return nil
+}
+
+func (s *Handler) TriggerNow(name string) error {
+	obj := s.FindObject(name)
+	if obj == nil {
+		return ErrJobNotFound
+	}
+
+	obj.TriggerNow()
+	return nil
+}
+
+func (s *Handler) activateObject() {
+	defer s.lock.Unlock()
+	s.lock.Lock()
+
+	if s.newObj == nil {
+		return
+	}
 
 	if s.activeObj == nil {
 		s.activeObj = make(map[uint64]*Object)
@@ -163,40 +728,105 @@ func (s *Handler) procObjects(p Priority) {
 		s.waiter.Done()
 	}()
 
+	for !s.termination.Load() {
+		s.procOnce()
+		s.sleep(time.Millisecond * time.Duration(p))
+	}
+}
+
+// procOnce 활성화된 잡 중 발생 시각이 된 잡을 한번씩 실행
+func (s *Handler) procOnce() {
 	var stopObjects []*Object
 
-	for !s.termination {
-		s.activateObject()
-		now := time.Now().In(time.UTC).UnixNano()
-		for _, obj := range s.activeObj {
-			if obj.stopFlag {
-				stopObjects = append(stopObjects, obj)
+	s.activateObject()
+	for _, obj := range s.activeObj {
+		if obj.stopFlag.Load() {
+			stopObjects = append(stopObjects, obj)
+			continue
+		}
+
+		now := s.now()
+		if obj.triggered.CompareAndSwap(true, false) {
+			s.handleResult(obj, s.execute(obj), now)
+			if obj.objType == intervalType {
+				obj.reschedule(now)
+			}
+			s.afterFire(obj)
+			continue
+		}
+
+		if obj.isDue(now) {
+			// 일시정지된 once 잡은 건너뛰면 사라지므로 재개될 때까지 발생 시각을 그대로 둠 (재개하면 바로 실행됨)
+			if obj.objType == onceType && obj.paused.Load() {
 				continue
 			}
-			if obj.objType == intervalType {
-				if now >= obj.lastTickTime {
-					obj.completion()
-					obj.lastTickTime = now + obj.interval
-				}
-			} else {
-				if now >= obj.nextEvent.UnixNano() {
-					tomorrow := time.Now().In(time.UTC).Add(24 * time.Hour)
-					obj.completion()
-					obj.nextEvent = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), obj.nextEvent.Hour(), obj.nextEvent.Minute(), obj.nextEvent.Second(), 0, time.UTC)
+
+			fireTime := obj.fireTime()
+			run := !obj.paused.Load()
+
+			var lease Lease
+			if run {
+				var err error
+				if lease, run, err = s.acquireFire(obj, fireTime); err != nil {
+					obj.deferFire(now.Add(lockRetryInterval))
+					continue
 				}
 			}
-		}
 
-		if stopObjects != nil {
-			for _, obj := range stopObjects {
-				s.removeObject(obj)
+			obj.reschedule(now)
+			obj.resetRetry()
+			if run {
+				s.handleResult(obj, s.executeLeased(obj, lease), now)
 			}
+			s.afterFire(obj)
+			continue
 		}
 
-		time.Sleep(time.Millisecond * time.Duration(p))
+		if obj.isRetryDue(now) && !obj.paused.Load() {
+			s.handleResult(obj, s.execute(obj), now)
+			s.afterFire(obj)
+		}
+	}
+
+	if stopObjects != nil {
+		for _, obj := range stopObjects {
+			s.removeObject(obj)
+		}
 	}
 }
 
+// nextDue 활성화된 잡 중 가장 빠른 다음 실행 시각
+func (s *Handler) nextDue() (time.Time, bool) {
+	defer s.lock.Unlock()
+
+	s.lock.Lock()
+	var (
+		result time.Time
+		found  bool
+	)
+
+	objects := make([]*Object, 0, len(s.activeObj)+len(s.newObj))
+	for _, obj := range s.activeObj {
+		objects = append(objects, obj)
+	}
+	objects = append(objects, s.newObj...)
+
+	for _, obj := range objects {
+		if obj.triggered.Load() {
+			return s.now(), true
+		}
+		if due, ok := obj.nextDue(); ok && (!found || due.Before(result)) {
+			result = due
+			found = true
+		}
+	}
+
+	return result, found
+}
+
 func (s *Handler) removeObject(obj *Object) {
+	defer s.lock.Unlock()
+
+	s.lock.Lock()
 	delete(s.activeObj, obj.key)
 }
@@ -0,0 +1,391 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testStart = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// memoryStore 테스트용 JobStore
+type memoryStore struct {
+	lock    sync.Mutex
+	records map[string]JobRecord
+}
+
+func newMemoryStore(records ...JobRecord) *memoryStore {
+	result := new(memoryStore)
+	result.records = make(map[string]JobRecord)
+	for _, record := range records {
+		result.records[record.Name] = record
+	}
+
+	return result
+}
+
+func (m *memoryStore) Save(record JobRecord) error {
+	m.lock.Lock()
+	m.records[record.Name] = record
+	m.lock.Unlock()
+	return nil
+}
+
+func (m *memoryStore) Delete(name string) error {
+	m.lock.Lock()
+	delete(m.records, name)
+	m.lock.Unlock()
+	return nil
+}
+
+func (m *memoryStore) Load() ([]JobRecord, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	result := make([]JobRecord, 0, len(m.records))
+	for _, record := range m.records {
+		result = append(result, record)
+	}
+	return result, nil
+}
+
+func (m *memoryStore) has(name string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	_, ok := m.records[name]
+	return ok
+}
+
+// clockLocker FakeClock 기준으로 만료되는 락. failures 만큼 Acquire 가 백엔드 에러를 냄
+type clockLocker struct {
+	clock    *FakeClock
+	lock     sync.Mutex
+	expire   map[string]time.Time
+	failures int
+	keys     []string
+	renews   int
+	releases int
+}
+
+type clockLease struct {
+	locker *clockLocker
+	key    string
+}
+
+func newClockLocker(clock *FakeClock) *clockLocker {
+	result := new(clockLocker)
+	result.clock = clock
+	result.expire = make(map[string]time.Time)
+
+	return result
+}
+
+func (l *clockLocker) Acquire(_ context.Context, key string, ttl time.Duration) (Lease, error) {
+	defer l.lock.Unlock()
+
+	l.lock.Lock()
+	l.keys = append(l.keys, key)
+	if l.failures > 0 {
+		l.failures--
+		return nil, errors.New("backend down")
+	}
+
+	now := l.clock.Now()
+	if expire, ok := l.expire[key]; ok && now.Before(expire) {
+		return nil, ErrLockNotAcquired
+	}
+
+	l.expire[key] = now.Add(ttl)
+	return &clockLease{locker: l, key: key}, nil
+}
+
+func (c *clockLease) Renew(_ context.Context, ttl time.Duration) error {
+	defer c.locker.lock.Unlock()
+
+	c.locker.lock.Lock()
+	c.locker.renews++
+	c.locker.expire[c.key] = c.locker.clock.Now().Add(ttl)
+	return nil
+}
+
+func (c *clockLease) Release(context.Context) error {
+	defer c.locker.lock.Unlock()
+
+	c.locker.lock.Lock()
+	c.locker.releases++
+	delete(c.locker.expire, c.key)
+	return nil
+}
+
+func (l *clockLocker) counts() (renews, releases int) {
+	defer l.lock.Unlock()
+
+	l.lock.Lock()
+	return l.renews, l.releases
+}
+
+func newTestHandler(clock *FakeClock) *Handler {
+	result := new(Handler)
+	result.SetClock(clock)
+
+	return result
+}
+
+func TestPauseResume(t *testing.T) {
+	clock := NewFakeClock(testStart)
+	handler := newTestHandler(clock)
+	handler.Run(PriorityNormal)
+	defer handler.Stop()
+
+	var runs atomic.Int32
+	handler.NewObject(CreateObjectByInterval(1000, func() { runs.Add(1) }).SetName("tick"))
+
+	clock.Advance(3 * time.Second)
+	if got := runs.Load(); got != 3 {
+		t.Fatalf("runs = %d, want 3", got)
+	}
+
+	if err := handler.Pause("tick"); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(5 * time.Second)
+	if got := runs.Load(); got != 3 {
+		t.Fatalf("paused job ran: runs = %d", got)
+	}
+	if jobs := handler.Jobs(); len(jobs) != 1 || !jobs[0].Paused {
+		t.Fatalf("Jobs = %+v", jobs)
+	}
+
+	if err := handler.Resume("tick"); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(0)
+	if got := runs.Load(); got != 4 {
+		t.Fatalf("resumed job did not run once: runs = %d", got)
+	}
+
+	if err := handler.Pause("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("Pause(missing) = %v", err)
+	}
+}
+
+func TestPausedOnceJobWaitsForResume(t *testing.T) {
+	clock := NewFakeClock(testStart)
+	store := newMemoryStore()
+	handler := newTestHandler(clock)
+	handler.SetJobStore(store, MisfireFireOnce)
+
+	var runs atomic.Int32
+	handler.RegisterJob("once", func(context.Context, json.RawMessage) error {
+		runs.Add(1)
+		return nil
+	})
+	handler.Run(PriorityNormal)
+	defer handler.Stop()
+
+	if err := handler.ScheduleOnce("event", "once", testStart.Add(time.Minute), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := handler.Pause("event"); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(time.Hour)
+	if runs.Load() != 0 || !store.has("event") || handler.FindObject("event") == nil {
+		t.Fatalf("paused once job: runs = %d, stored = %v", runs.Load(), store.has("event"))
+	}
+
+	if err := handler.Resume("event"); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(0)
+	if runs.Load() != 1 || store.has("event") {
+		t.Fatalf("resumed once job: runs = %d, stored = %v", runs.Load(), store.has("event"))
+	}
+}
+
+func TestScheduleReplacesJob(t *testing.T) {
+	clock := NewFakeClock(testStart)
+	handler := newTestHandler(clock)
+	handler.SetJobStore(newMemoryStore(), MisfireFireOnce)
+
+	var calls [2]atomic.Int32
+	for i := range calls {
+		handler.RegisterJob(fmt.Sprintf("kind%d", i), func(context.Context, json.RawMessage) error {
+			calls[i].Add(1)
+			return nil
+		})
+	}
+	handler.Run(PriorityNormal)
+	defer handler.Stop()
+
+	if err := handler.ScheduleInterval("job", "kind0", 1000, nil); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(0)
+	if err := handler.ScheduleInterval("job", "kind1", 1000, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// 교체된 잡은 다음 tick 까지 activeObj 에 남아있지만 Pause 는 새 잡에 적용되어야 함
+	if err := handler.Pause("job"); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(3 * time.Second)
+	if calls[0].Load() != 0 || calls[1].Load() != 0 {
+		t.Fatalf("calls = %d, %d", calls[0].Load(), calls[1].Load())
+	}
+}
+
+func TestMisfirePolicy(t *testing.T) {
+	// initial, later 는 interval 과 once 잡의 실행 횟수 합
+	cases := []struct {
+		name    string
+		policy  MisfirePolicy
+		initial int32
+		later   int32
+	}{
+		{name: "fire once", policy: MisfireFireOnce, initial: 2, later: 3},
+		{name: "fire all", policy: MisfireFireAll, initial: 5, later: 6},
+		{name: "skip", policy: MisfireSkip, initial: 0, later: 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clock := NewFakeClock(testStart)
+			store := newMemoryStore(
+				// 3.5 초 전에 발생했어야 하는 1 초 간격 잡 -> 4 번 놓침
+				JobRecord{Name: "interval", Kind: "interval", Type: intervalType.String(), Interval: 1000, NextFire: testStart.Add(-3500 * time.Millisecond)},
+				JobRecord{Name: "once", Kind: "once", Type: onceType.String(), NextFire: testStart.Add(-time.Hour)},
+			)
+
+			handler := newTestHandler(clock)
+			handler.SetJobStore(store, c.policy)
+
+			var intervalRuns, onceRuns atomic.Int32
+			handler.RegisterJob("interval", func(context.Context, json.RawMessage) error {
+				intervalRuns.Add(1)
+				return nil
+			})
+			handler.RegisterJob("once", func(context.Context, json.RawMessage) error {
+				onceRuns.Add(1)
+				return nil
+			})
+			handler.Run(PriorityNormal)
+			defer handler.Stop()
+
+			clock.Advance(0)
+			if got := intervalRuns.Load() + onceRuns.Load(); got != c.initial {
+				t.Fatalf("runs after restart = %d (interval %d, once %d), want %d", got, intervalRuns.Load(), onceRuns.Load(), c.initial)
+			}
+
+			clock.Advance(time.Second)
+			if got := intervalRuns.Load() + onceRuns.Load(); got != c.later {
+				t.Fatalf("runs after 1s = %d, want %d", got, c.later)
+			}
+			if store.has("once") {
+				t.Fatal("missed once job is still stored")
+			}
+
+			// 다음 발생 시각이 저장소에 반영됨
+			records, _ := store.Load()
+			for _, record := range records {
+				if record.Name == "interval" && !record.NextFire.After(clock.Now()) {
+					t.Fatalf("interval next fire %v is not after %v", record.NextFire, clock.Now())
+				}
+			}
+		})
+	}
+}
+
+func TestSingletonLeaseRenewAndRelease(t *testing.T) {
+	clock := NewFakeClock(testStart)
+	locker := newClockLocker(clock)
+
+	var runs atomic.Int32
+	replicas := make([]*Handler, 2)
+	for i := range replicas {
+		replicas[i] = newTestHandler(clock)
+		replicas[i].SetLocker(locker)
+		replicas[i].Run(PriorityNormal)
+		defer replicas[i].Stop()
+
+		replicas[i].NewObject(CreateObjectByInterval(10000, func() {
+			runs.Add(1)
+			clock.Sleep(2500 * time.Millisecond)
+		}).SetName("report").SetClusterSingleton(3 * time.Second))
+	}
+
+	// 10 초에 한 레플리카만 실행. 실행 중 11, 12 초에 연장하고 마지막 연장부터 ttl 이 지난 15 초에 해제
+	clock.Advance(10 * time.Second)
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("runs = %d, want 1", got)
+	}
+	if renews, releases := locker.counts(); renews != 2 || releases != 0 {
+		t.Fatalf("renews = %d, releases = %d, want 2, 0", renews, releases)
+	}
+
+	clock.AdvanceTo(testStart.Add(14900 * time.Millisecond))
+	if _, releases := locker.counts(); releases != 0 {
+		t.Fatalf("released before ttl: releases = %d", releases)
+	}
+
+	clock.AdvanceTo(testStart.Add(15 * time.Second))
+	if renews, releases := locker.counts(); renews != 2 || releases != 1 {
+		t.Fatalf("renews = %d, releases = %d, want 2, 1", renews, releases)
+	}
+}
+
+func TestSingletonLockBackendError(t *testing.T) {
+	clock := NewFakeClock(testStart)
+	locker := newClockLocker(clock)
+	locker.failures = 2
+
+	handler := newTestHandler(clock)
+	handler.SetLocker(locker)
+
+	var hookErrors atomic.Int32
+	handler.SetErrorHook(func(string, int, error) { hookErrors.Add(1) })
+	handler.Run(PriorityNormal)
+	defer handler.Stop()
+
+	var runs atomic.Int32
+	handler.NewObject(CreateObjectByInterval(60000, func() { runs.Add(1) }).SetName("sync").SetClusterSingleton(time.Second))
+
+	// 60 초에 두번 실패하고 lockRetryInterval 마다 같은 발생 시각으로 다시 시도
+	clock.Advance(60 * time.Second)
+	if runs.Load() != 0 || hookErrors.Load() != 1 {
+		t.Fatalf("runs = %d, hook errors = %d", runs.Load(), hookErrors.Load())
+	}
+
+	clock.Advance(2 * lockRetryInterval)
+	if runs.Load() != 1 || hookErrors.Load() != 2 {
+		t.Fatalf("runs = %d, hook errors = %d", runs.Load(), hookErrors.Load())
+	}
+
+	locker.lock.Lock()
+	keys := append([]string(nil), locker.keys...)
+	locker.lock.Unlock()
+	if len(keys) != 3 || keys[0] != keys[2] {
+		t.Fatalf("lock keys = %v, want the same key retried", keys)
+	}
+}
+
+func TestUnnamedSingletonPanics(t *testing.T) {
+	handler := newTestHandler(NewFakeClock(testStart))
+	handler.Run(PriorityNormal)
+	defer handler.Stop()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewObject did not panic")
+		}
+	}()
+	handler.NewObject(CreateObjectByInterval(1000, func() {}).SetClusterSingleton(time.Second))
+}
@@ -0,0 +1,285 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MisfirePolicy 서버가 내려가 있는 동안 발생 시각이 지나간 잡의 처리 방법
+type MisfirePolicy int
+
+const (
+	MisfireFireOnce MisfirePolicy = 0 + iota // 놓친 횟수와 무관하게 한번 실행
+	MisfireFireAll                           // 놓친 횟수만큼 실행
+	MisfireSkip                              // 실행하지 않고 다음 스케쥴로 넘어감
+)
+
+// JobRecord 저장소에 기록되는 잡. Kind 는 RegisterJob 으로 등록한 함수 이름
+type JobRecord struct {
+	Name     string          `json:"name"`
+	Kind     string          `json:"kind"`
+	Type     string          `json:"type"`
+	Interval int64           `json:"interval,omitempty"`
+	Hour     int             `json:"hour,omitempty"`
+	Minute   int             `json:"minute,omitempty"`
+	Second   int             `json:"second,omitempty"`
+	NextFire time.Time       `json:"nextFire"`
+	Args     json.RawMessage `json:"args,omitempty"`
+}
+
+// JobStore 잡 영속화 저장소. 구현체: FileJobStore, xmysql.JobStore
+type JobStore interface {
+	Save(record JobRecord) error
+	Delete(name string) error
+	Load() ([]JobRecord, error)
+}
+
+// JobFunc 영속화된 잡이 실행할 함수. args 는 스케쥴 시 넘긴 값을 json 으로 직렬화한 것
+type JobFunc func(ctx context.Context, args json.RawMessage) error
+
+var (
+	ErrJobKindNotFound = errors.New("scheduler: job kind is not registered")
+	ErrNoJobStore      = errors.New("scheduler: job store is not set")
+)
+
+/*
+## 사용법 ##
+	store, _ := scheduler.NewFileJobStore("./data/jobs.json")
+	handler := new(scheduler.Handler)
+	handler.SetJobStore(store, scheduler.MisfireFireOnce)
+	handler.RegisterJob("expireEvent", func(ctx context.Context, args json.RawMessage) error {
+		var eventID int64
+		_ = json.Unmarshal(args, &eventID)
+		return expireEvent(ctx, eventID)
+	})
+	handler.Run(scheduler.PriorityNormal)		// 저장된 잡을 여기서 다시 불러옴
+
+	_ = handler.ScheduleOnce("expire-event-1004", "expireEvent", time.Now().Add(72*time.Hour), 1004)
+*/
+
+// SetJobStore Run 전에 호출해야 함
+func (s *Handler) SetJobStore(store JobStore, policy MisfirePolicy) {
+	s.store = store
+	s.misfire = policy
+}
+
+// RegisterJob 영속화된 잡이 재시작 후에 실행할 함수를 kind 이름으로 등록. Run 전에 호출해야 함
+func (s *Handler) RegisterJob(kind string, fn JobFunc) {
+	if s.jobFuncs == nil {
+		s.jobFuncs = make(map[string]JobFunc)
+	}
+
+	s.jobFuncs[kind] = fn
+}
+
+// ScheduleOnce at 에 한번 실행되는 잡을 저장하고 등록
+func (s *Handler) ScheduleOnce(name, kind string, at time.Time, args any) error {
+	return s.schedule(JobRecord{
+		Name:     name,
+		Kind:     kind,
+		Type:     onceType.String(),
+		NextFire: at.In(time.UTC),
+	}, args)
+}
+
+func (s *Handler) ScheduleInterval(name, kind string, milliSecondInterval int64, args any) error {
+	return s.schedule(JobRecord{
+		Name:     name,
+		Kind:     kind,
+		Type:     intervalType.String(),
+		Interval: milliSecondInterval,
+		NextFire: s.now().Add(time.Duration(milliSecondInterval * milliSecondToNanoSecond)),
+	}, args)
+}
+
+func (s *Handler) ScheduleEveryDay(name, kind string, hour, minute, second int, args any) error {
+	tomorrow := s.now().Add(24 * time.Hour)
+	return s.schedule(JobRecord{
+		Name:     name,
+		Kind:     kind,
+		Type:     everyDayType.String(),
+		Hour:     hour,
+		Minute:   minute,
+		Second:   second,
+		NextFire: time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), hour, minute, second, 0, time.UTC),
+	}, args)
+}
+
+// Unschedule 영속화된 잡을 저장소와 스케쥴러에서 제거
+func (s *Handler) Unschedule(name string) error {
+	if s.store == nil {
+		return ErrNoJobStore
+	}
+
+	if obj := s.FindObject(name); obj != nil {
+		s.DeleteObject(obj)
+	}
+
+	return s.store.Delete(name)
+}
+
+func (s *Handler) schedule(record JobRecord, args any) error {
+	if s.store == nil {
+		return ErrNoJobStore
+	}
+
+	if args != nil {
+		b, err := json.Marshal(args)
+		if err != nil {
+			return err
+		}
+		record.Args = b
+	}
+
+	obj, err := s.objectFromRecord(record)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Save(record); err != nil {
+		return err
+	}
+
+	if old := s.FindObject(record.Name); old != nil {
+		s.DeleteObject(old)
+	}
+
+	s.NewObject(obj)
+	return nil
+}
+
+func (s *Handler) objectFromRecord(record JobRecord) (*Object, error) {
+	fn, ok := s.jobFuncs[record.Kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrJobKindNotFound, record.Kind)
+	}
+
+	args := record.Args
+	obj := new(Object)
+	obj.name = record.Name
+	obj.completion = func(ctx context.Context) error {
+		return fn(ctx, args)
+	}
+	obj.record = &record
+	obj.restored = true
+
+	switch record.Type {
+	case intervalType.String():
+		obj.objType = intervalType
+		obj.interval = record.Interval * milliSecondToNanoSecond
+		obj.lastTickTime = record.NextFire.UnixNano()
+	case everyDayType.String():
+		obj.objType = everyDayType
+		obj.nextEvent = record.NextFire.In(time.UTC)
+	case onceType.String():
+		obj.objType = onceType
+		obj.nextEvent = record.NextFire.In(time.UTC)
+	default:
+		return nil, fmt.Errorf("scheduler: unknown job type %s", record.Type)
+	}
+
+	return obj, nil
+}
+
+// loadStore 저장된 잡을 불러와 misfire 정책을 적용한 뒤 등록
+func (s *Handler) loadStore() {
+	records, err := s.store.Load()
+	if err != nil {
+		s.reportError("", err)
+		return
+	}
+
+	now := s.now()
+	for _, record := range records {
+		obj, err := s.objectFromRecord(record)
+		if err != nil {
+			s.reportError(record.Name, err)
+			continue
+		}
+
+		if !record.NextFire.After(now) && !s.applyMisfire(obj, record, now) {
+			if err := s.store.Delete(record.Name); err != nil {
+				s.reportError(record.Name, err)
+			}
+			continue
+		}
+
+		s.NewObject(obj)
+	}
+}
+
+// applyMisfire 놓친 잡의 다음 실행을 정함. 더 이상 실행할 필요가 없으면 false
+func (s *Handler) applyMisfire(obj *Object, record JobRecord, now time.Time) bool {
+	var period time.Duration
+	switch obj.objType {
+	case onceType:
+		return s.misfire != MisfireSkip
+	case intervalType:
+		period = time.Duration(obj.interval)
+	case everyDayType:
+		period = 24 * time.Hour
+	}
+
+	if period <= 0 {
+		return true
+	}
+
+	missed := int(now.Sub(record.NextFire)/period) + 1
+	switch s.misfire {
+	case MisfireFireAll:
+		obj.catchUp = missed - 1
+	case MisfireSkip:
+		next := record.NextFire.Add(time.Duration(missed) * period)
+		if obj.objType == intervalType {
+			obj.lastTickTime = next.UnixNano()
+		} else {
+			obj.nextEvent = next
+		}
+	}
+
+	return true
+}
+
+// afterFire 영속화된 잡의 다음 발생 시각을 저장하고, 끝난 once 잡은 제거함
+func (s *Handler) afterFire(obj *Object) {
+	if obj.objType == onceType {
+		obj.lock.Lock()
+		pending := !obj.retryAt.IsZero()
+		obj.lock.Unlock()
+
+		if !pending {
+			obj.stopFlag.Store(true)
+			if obj.record != nil && s.store != nil {
+				if err := s.store.Delete(obj.name); err != nil {
+					s.reportError(obj.name, err)
+				}
+			}
+		}
+		return
+	}
+
+	if obj.record == nil || s.store == nil {
+		return
+	}
+
+	record := *obj.record
+	record.NextFire = obj.info().NextFire
+	if record.NextFire.Equal(obj.record.NextFire) {
+		return
+	}
+
+	if err := s.store.Save(record); err != nil {
+		s.reportError(obj.name, err)
+		return
+	}
+	obj.record = &record
+}
+
+func (s *Handler) reportError(name string, err error) {
+	if s.errorHook != nil {
+		s.errorHook(name, 0, err)
+	}
+}
@@ -28,6 +28,7 @@ type logger struct {
 	logs    []logObject
 	loc     *time.Location
 	sc      *scheduler.Handler
+	clock   scheduler.Clock
 	lock    *sync.Mutex
 }
 
@@ -48,13 +49,18 @@ func RunLogger(runningScheduler *scheduler.Handler, appName string, loc *time.Lo
 	runningScheduler.NewObject(obj)
 }
 
+// SetClock 로그 시각과 파일 교체에 사용할 시계 지정 (테스트에서 scheduler.FakeClock 을 넘김)
+func SetClock(clock scheduler.Clock) {
+	curLogger.clock = clock
+}
+
 func StopLogger() {
 	curLogger.procSchedule()
 }
 
 func printf(lv LogLevel, format string, a ...interface{}) {
 	str := fmt.Sprintf(format, a...)
-	now := time.Now().In(curLogger.loc)
+	now := curLogger.now()
 	timeString := timeToString(now)
 
 	obj := logObject{
@@ -106,10 +112,17 @@ func timeToString(tm time.Time) string {
 	return fmt.Sprintf("%04d.%02d.%02d %02d:%02d:%02d %s%d/%s", tm.Year(), tm.Month(), tm.Day(), tm.Hour(), tm.Minute(), tm.Second(), sign, offset, zoneName)
 }
 
+func (l *logger) now() time.Time {
+	if l.clock == nil {
+		return time.Now().In(l.loc)
+	}
+	return l.clock.Now().In(l.loc)
+}
+
 func (l *logger) procSchedule() {
 	defer func() {
 		if r := recover(); r != nil {
-			now := time.Now().In(l.loc)
+			now := l.now()
 			fmt.Printf("[%s] [FATAL] %s\n", timeToString(now), r)
 		}
 	}()
@@ -125,7 +138,7 @@ func (l *logger) procSchedule() {
 		"FATAL",
 	}
 
-	now := time.Now().In(l.loc)
+	now := l.now()
 	filePath := fmt.Sprintf("./log/%s_%04d-%02d-%02d.log", l.appName, now.Year(), now.Month(), now.Day())
 
 	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
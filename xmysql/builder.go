@@ -0,0 +1,670 @@
+package xmysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+## 사용법 ##
+sqlString, args, err := xmysql.Select("id", "name", "level").
+	From("user").
+	Where(xmysql.Eq("server_id", 3), xmysql.Or(xmysql.Gte("level", 10), xmysql.In("grade", "gold", "vip"))).
+	OrderByDesc("level").
+	Limit(20).
+	Build()
+// SELECT `id`, `name`, `level` FROM `user` WHERE `server_id` = ? AND (`level` >= ? OR `grade` IN (?, ?)) ORDER BY `level` DESC LIMIT 20
+
+handler.Query(&xmysql.QueryExecutor{SqlString: sqlString, Args: args, OnQuery: onQuery})
+
+// 벌크 INSERT 는 max_allowed_packet 에 맞춰 나눔
+insert := xmysql.InsertInto("item").Columns("uid", "item_id", "count").OnDuplicateKeyUpdate("count")
+for _, item := range items {
+	insert.Values(item.UID, item.ItemID, item.Count)
+}
+
+maxPacket, _ := handler.MaxAllowedPacket(ctx)
+statements, err := insert.Chunks(maxPacket)
+for _, statement := range statements {
+	handler.ExecuteContext(ctx, statement.SQL, onExec, onError, statement.Args...)
+}
+*/
+
+const (
+	// maxPlaceholders mysql 프리페어드 스테이트먼트 한 개의 최대 파라미터 수
+	maxPlaceholders = 65535
+	// packetHeadroom 패킷 헤더와 프로토콜 오버헤드를 위해 남겨두는 여유
+	packetHeadroom = 1024
+)
+
+var (
+	ErrNoTable        = errors.New("xmysql: builder has no table")
+	ErrNoColumns      = errors.New("xmysql: builder has no columns")
+	ErrNoValues       = errors.New("xmysql: builder has no values")
+	ErrPacketTooLarge = errors.New("xmysql: single row exceeds max_allowed_packet")
+)
+
+// Statement 빌더가 만든 SQL 과 파라미터
+type Statement struct {
+	SQL  string
+	Args []any
+}
+
+// Cond WHERE 절의 조건식. 여러 개를 Where 에 넘기면 AND 로 묶임
+// compound 는 Raw 나 And, Or 처럼 다른 조건과 이을 때 괄호로 감싸야 우선순위가 유지되는 식
+type Cond struct {
+	sql      string
+	args     []any
+	compound bool
+}
+
+func Eq(column string, value any) Cond  { return compare(column, "=", value) }
+func Ne(column string, value any) Cond  { return compare(column, "<>", value) }
+func Gt(column string, value any) Cond  { return compare(column, ">", value) }
+func Gte(column string, value any) Cond { return compare(column, ">=", value) }
+func Lt(column string, value any) Cond  { return compare(column, "<", value) }
+func Lte(column string, value any) Cond { return compare(column, "<=", value) }
+
+func Like(column string, pattern string) Cond { return compare(column, "LIKE", pattern) }
+
+func IsNull(column string) Cond    { return Cond{sql: quoteIdentifier(column) + " IS NULL"} }
+func IsNotNull(column string) Cond { return Cond{sql: quoteIdentifier(column) + " IS NOT NULL"} }
+
+func Between(column string, from, to any) Cond {
+	return Cond{sql: quoteIdentifier(column) + " BETWEEN ? AND ?", args: []any{from, to}}
+}
+
+// In 값이 없으면 항상 거짓
+func In(column string, values ...any) Cond {
+	return inList(column, "IN", "1 = 0", values)
+}
+
+// NotIn 값이 없으면 항상 참
+func NotIn(column string, values ...any) Cond {
+	return inList(column, "NOT IN", "1 = 1", values)
+}
+
+// InSlice []int64 처럼 타입이 있는 슬라이스를 그대로 넘길 때
+func InSlice[T any](column string, values []T) Cond {
+	return In(column, toAnySlice(values)...)
+}
+
+// Raw 빌더가 지원하지 않는 조건식. 식 안의 식별자는 직접 이스케이프해야 함
+// 다른 조건과 이어질 때는 괄호로 감싸므로 OR 가 들어 있어도 됨
+func Raw(sqlString string, args ...any) Cond {
+	return Cond{sql: sqlString, args: args, compound: true}
+}
+
+// And 조건이 없으면 항상 참 (1 = 1), Or 는 조건이 없으면 항상 거짓 (1 = 0)
+func And(conds ...Cond) Cond { return group(" AND ", "1 = 1", conds) }
+func Or(conds ...Cond) Cond  { return group(" OR ", "1 = 0", conds) }
+
+func Not(cond Cond) Cond {
+	return Cond{sql: "NOT (" + cond.sql + ")", args: cond.args}
+}
+
+func compare(column, op string, value any) Cond {
+	return Cond{sql: quoteIdentifier(column) + " " + op + " ?", args: []any{value}}
+}
+
+func inList(column, op, empty string, values []any) Cond {
+	if len(values) == 0 {
+		return Cond{sql: empty}
+	}
+
+	return Cond{sql: quoteIdentifier(column) + " " + op + " (" + placeholders(len(values)) + ")", args: values}
+}
+
+func group(sep, empty string, conds []Cond) Cond {
+	if len(conds) == 0 {
+		return Cond{sql: empty}
+	}
+	if len(conds) == 1 {
+		return conds[0]
+	}
+
+	sqlString, args := joinConds(sep, conds)
+	return Cond{sql: sqlString, args: args, compound: true}
+}
+
+// joinConds 조건이 둘 이상이면 compound 조건을 괄호로 감싸서 sep 으로 이음
+func joinConds(sep string, conds []Cond) (string, []any) {
+	if len(conds) == 1 {
+		return conds[0].sql, conds[0].args
+	}
+
+	var args []any
+	parts := make([]string, 0, len(conds))
+	for _, cond := range conds {
+		if cond.compound {
+			parts = append(parts, "("+cond.sql+")")
+		} else {
+			parts = append(parts, cond.sql)
+		}
+		args = append(args, cond.args...)
+	}
+
+	return strings.Join(parts, sep), args
+}
+
+func toAnySlice[T any](values []T) []any {
+	result := make([]any, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+
+	return result
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func quoteColumns(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		if column == "*" {
+			quoted[i] = column
+		} else if table, found := strings.CutSuffix(column, ".*"); found {
+			quoted[i] = quoteIdentifier(table) + ".*"
+		} else {
+			quoted[i] = quoteIdentifier(column)
+		}
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// whereClause, orderClause Select, Update, Delete 가 같이 쓰는 절
+type whereClause struct {
+	conds []Cond
+}
+
+func (w *whereClause) write(sb *strings.Builder, args *[]any) {
+	if len(w.conds) == 0 {
+		return
+	}
+
+	sqlString, condArgs := joinConds(" AND ", w.conds)
+	sb.WriteString(" WHERE ")
+	sb.WriteString(sqlString)
+	*args = append(*args, condArgs...)
+}
+
+type orderClause struct {
+	orders []string
+	limit  int64
+	offset int64
+}
+
+func (o *orderClause) write(sb *strings.Builder) {
+	if len(o.orders) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(o.orders, ", "))
+	}
+	if o.limit > 0 {
+		sb.WriteString(" LIMIT ")
+		sb.WriteString(strconv.FormatInt(o.limit, 10))
+	} else if o.offset > 0 {
+		// mysql 은 LIMIT 없이 OFFSET 을 쓸 수 없음
+		sb.WriteString(" LIMIT 18446744073709551615")
+	}
+	if o.offset > 0 {
+		sb.WriteString(" OFFSET ")
+		sb.WriteString(strconv.FormatInt(o.offset, 10))
+	}
+}
+
+type SelectBuilder struct {
+	whereClause
+	orderClause
+	columns   []string
+	exprArgs  []any
+	table     string
+	groupBy   []string
+	having    []Cond
+	forUpdate bool
+}
+
+// Select 컬럼 이름은 이스케이프 됨. 비우거나 "*" 이면 전체 컬럼
+func Select(columns ...string) *SelectBuilder {
+	result := new(SelectBuilder)
+	if len(columns) > 0 {
+		result.columns = append(result.columns, quoteColumns(columns))
+	}
+
+	return result
+}
+
+// ColumnExpr COUNT(*) 같은 식. 이스케이프 하지 않음
+func (b *SelectBuilder) ColumnExpr(expr string, args ...any) *SelectBuilder {
+	b.columns = append(b.columns, expr)
+	b.exprArgs = append(b.exprArgs, args...)
+	return b
+}
+
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.table = table
+	return b
+}
+
+// Where 여러 번 호출하면 AND 로 이어짐
+func (b *SelectBuilder) Where(conds ...Cond) *SelectBuilder {
+	b.conds = append(b.conds, conds...)
+	return b
+}
+
+func (b *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	b.groupBy = append(b.groupBy, columns...)
+	return b
+}
+
+func (b *SelectBuilder) Having(conds ...Cond) *SelectBuilder {
+	b.having = append(b.having, conds...)
+	return b
+}
+
+func (b *SelectBuilder) OrderBy(column string) *SelectBuilder {
+	b.orders = append(b.orders, quoteIdentifier(column))
+	return b
+}
+
+func (b *SelectBuilder) OrderByDesc(column string) *SelectBuilder {
+	b.orders = append(b.orders, quoteIdentifier(column)+" DESC")
+	return b
+}
+
+func (b *SelectBuilder) Limit(limit int64) *SelectBuilder {
+	b.limit = limit
+	return b
+}
+
+func (b *SelectBuilder) Offset(offset int64) *SelectBuilder {
+	b.offset = offset
+	return b
+}
+
+// ForUpdate SELECT ... FOR UPDATE. 트랜잭션 안에서만 의미가 있음
+func (b *SelectBuilder) ForUpdate() *SelectBuilder {
+	b.forUpdate = true
+	return b
+}
+
+func (b *SelectBuilder) Build() (string, []any, error) {
+	if b.table == "" {
+		return "", nil, ErrNoTable
+	}
+
+	var sb strings.Builder
+	args := append([]any(nil), b.exprArgs...)
+
+	sb.WriteString("SELECT ")
+	if len(b.columns) == 0 {
+		sb.WriteString("*")
+	} else {
+		sb.WriteString(strings.Join(b.columns, ", "))
+	}
+	sb.WriteString(" FROM ")
+	sb.WriteString(quoteIdentifier(b.table))
+
+	b.whereClause.write(&sb, &args)
+
+	if len(b.groupBy) > 0 {
+		sb.WriteString(" GROUP BY ")
+		sb.WriteString(quoteColumns(b.groupBy))
+	}
+	if len(b.having) > 0 {
+		sqlString, havingArgs := joinConds(" AND ", b.having)
+		sb.WriteString(" HAVING ")
+		sb.WriteString(sqlString)
+		args = append(args, havingArgs...)
+	}
+
+	b.orderClause.write(&sb)
+
+	if b.forUpdate {
+		sb.WriteString(" FOR UPDATE")
+	}
+
+	return sb.String(), args, nil
+}
+
+type assignment struct {
+	column string
+	expr   string
+	args   []any
+}
+
+type InsertBuilder struct {
+	table   string
+	columns []string
+	rows    [][]any
+	ignore  bool
+	onDup   []assignment
+	err     error
+}
+
+func InsertInto(table string) *InsertBuilder {
+	result := new(InsertBuilder)
+	result.table = table
+
+	return result
+}
+
+func (b *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
+// Values 한 행. 여러 번 호출하면 multi-row INSERT 가 됨
+func (b *InsertBuilder) Values(values ...any) *InsertBuilder {
+	if len(values) != len(b.columns) && b.err == nil {
+		b.err = fmt.Errorf("xmysql: insert row %d has %d values for %d columns", len(b.rows), len(values), len(b.columns))
+	}
+	b.rows = append(b.rows, values)
+	return b
+}
+
+// Ignore INSERT IGNORE
+func (b *InsertBuilder) Ignore() *InsertBuilder {
+	b.ignore = true
+	return b
+}
+
+// OnDuplicateKeyUpdate 키가 겹치면 넣으려던 값으로 컬럼을 덮어씀 (col = VALUES(col))
+func (b *InsertBuilder) OnDuplicateKeyUpdate(columns ...string) *InsertBuilder {
+	for _, column := range columns {
+		quoted := quoteIdentifier(column)
+		b.onDup = append(b.onDup, assignment{column: column, expr: "VALUES(" + quoted + ")"})
+	}
+	return b
+}
+
+// OnDuplicateKeySet 키가 겹치면 컬럼을 식으로 갱신 (예: "`count` + VALUES(`count`)")
+func (b *InsertBuilder) OnDuplicateKeySet(column, expr string, args ...any) *InsertBuilder {
+	b.onDup = append(b.onDup, assignment{column: column, expr: expr, args: args})
+	return b
+}
+
+func (b *InsertBuilder) validate() error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.table == "" {
+		return ErrNoTable
+	}
+	if len(b.columns) == 0 {
+		return ErrNoColumns
+	}
+	if len(b.rows) == 0 {
+		return ErrNoValues
+	}
+
+	return nil
+}
+
+func (b *InsertBuilder) prefix() string {
+	var sb strings.Builder
+	sb.WriteString("INSERT ")
+	if b.ignore {
+		sb.WriteString("IGNORE ")
+	}
+	sb.WriteString("INTO ")
+	sb.WriteString(quoteIdentifier(b.table))
+	sb.WriteString(" (")
+	sb.WriteString(quoteColumns(b.columns))
+	sb.WriteString(") VALUES ")
+
+	return sb.String()
+}
+
+func (b *InsertBuilder) suffix() (string, []any) {
+	if len(b.onDup) == 0 {
+		return "", nil
+	}
+
+	var args []any
+	parts := make([]string, len(b.onDup))
+	for i, a := range b.onDup {
+		parts[i] = quoteIdentifier(a.column) + " = " + a.expr
+		args = append(args, a.args...)
+	}
+
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(parts, ", "), args
+}
+
+func (b *InsertBuilder) statement(rows [][]any) Statement {
+	row := "(" + placeholders(len(b.columns)) + ")"
+	suffix, suffixArgs := b.suffix()
+
+	var sb strings.Builder
+	args := make([]any, 0, len(rows)*len(b.columns)+len(suffixArgs))
+
+	sb.WriteString(b.prefix())
+	for i, values := range rows {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(row)
+		args = append(args, values...)
+	}
+	sb.WriteString(suffix)
+	args = append(args, suffixArgs...)
+
+	return Statement{SQL: sb.String(), Args: args}
+}
+
+// Build 모든 행을 하나의 INSERT 로 만듦. 행이 많으면 Chunks 를 사용
+func (b *InsertBuilder) Build() (string, []any, error) {
+	if err := b.validate(); err != nil {
+		return "", nil, err
+	}
+
+	statement := b.statement(b.rows)
+	return statement.SQL, statement.Args, nil
+}
+
+// Chunks 한 문장이 maxPacket 바이트와 파라미터 65535 개를 넘지 않도록 행을 나눠 여러 INSERT 로 만듦
+// 크기는 값을 텍스트로 보냈을 때를 기준으로 넉넉하게 추정함
+func (b *InsertBuilder) Chunks(maxPacket int) ([]Statement, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+
+	suffix, suffixArgs := b.suffix()
+	base := len(b.prefix()) + len(suffix) + estimateArgs(suffixArgs) + packetHeadroom
+	rowSQL := len(b.columns)*3 + 2
+
+	maxRows := (maxPlaceholders - len(suffixArgs)) / len(b.columns)
+	if maxRows < 1 {
+		return nil, fmt.Errorf("xmysql: too many columns for one statement (%d)", len(b.columns))
+	}
+
+	var (
+		result []Statement
+		start  int
+		size   = base
+	)
+	for i, values := range b.rows {
+		rowSize := rowSQL + estimateArgs(values)
+		if base+rowSize > maxPacket {
+			return nil, fmt.Errorf("%w: row %d is about %d bytes", ErrPacketTooLarge, i, rowSize)
+		}
+
+		if i > start && (size+rowSize > maxPacket || i-start >= maxRows) {
+			result = append(result, b.statement(b.rows[start:i]))
+			start = i
+			size = base
+		}
+		size += rowSize
+	}
+	result = append(result, b.statement(b.rows[start:]))
+
+	return result, nil
+}
+
+// estimateArgs 인자를 SQL 텍스트로 보냈을 때의 대략적인 크기. 문자열은 모든 문자가 이스케이프 된다고 가정
+func estimateArgs(args []any) int {
+	result := 0
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case nil:
+			result += 4
+		case string:
+			result += len(v)*2 + 2
+		case []byte:
+			result += len(v)*2 + 3
+		case time.Time:
+			result += 28
+		case fmt.Stringer:
+			result += len(v.String())*2 + 2
+		default:
+			result += 24
+		}
+	}
+
+	return result
+}
+
+type UpdateBuilder struct {
+	whereClause
+	orderClause
+	table string
+	sets  []assignment
+}
+
+func Update(table string) *UpdateBuilder {
+	result := new(UpdateBuilder)
+	result.table = table
+
+	return result
+}
+
+func (b *UpdateBuilder) Set(column string, value any) *UpdateBuilder {
+	b.sets = append(b.sets, assignment{column: column, expr: "?", args: []any{value}})
+	return b
+}
+
+// SetExpr 컬럼을 식으로 갱신 (예: SetExpr("gold", "`gold` + ?", 100))
+func (b *UpdateBuilder) SetExpr(column, expr string, args ...any) *UpdateBuilder {
+	b.sets = append(b.sets, assignment{column: column, expr: expr, args: args})
+	return b
+}
+
+func (b *UpdateBuilder) Where(conds ...Cond) *UpdateBuilder {
+	b.conds = append(b.conds, conds...)
+	return b
+}
+
+func (b *UpdateBuilder) OrderBy(column string) *UpdateBuilder {
+	b.orders = append(b.orders, quoteIdentifier(column))
+	return b
+}
+
+func (b *UpdateBuilder) OrderByDesc(column string) *UpdateBuilder {
+	b.orders = append(b.orders, quoteIdentifier(column)+" DESC")
+	return b
+}
+
+func (b *UpdateBuilder) Limit(limit int64) *UpdateBuilder {
+	b.limit = limit
+	return b
+}
+
+func (b *UpdateBuilder) Build() (string, []any, error) {
+	if b.table == "" {
+		return "", nil, ErrNoTable
+	}
+	if len(b.sets) == 0 {
+		return "", nil, ErrNoValues
+	}
+
+	var (
+		sb   strings.Builder
+		args []any
+	)
+
+	sb.WriteString("UPDATE ")
+	sb.WriteString(quoteIdentifier(b.table))
+	sb.WriteString(" SET ")
+	for i, a := range b.sets {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(quoteIdentifier(a.column))
+		sb.WriteString(" = ")
+		sb.WriteString(a.expr)
+		args = append(args, a.args...)
+	}
+
+	b.whereClause.write(&sb, &args)
+	b.orderClause.write(&sb)
+
+	return sb.String(), args, nil
+}
+
+type DeleteBuilder struct {
+	whereClause
+	orderClause
+	table string
+}
+
+func DeleteFrom(table string) *DeleteBuilder {
+	result := new(DeleteBuilder)
+	result.table = table
+
+	return result
+}
+
+func (b *DeleteBuilder) Where(conds ...Cond) *DeleteBuilder {
+	b.conds = append(b.conds, conds...)
+	return b
+}
+
+func (b *DeleteBuilder) OrderBy(column string) *DeleteBuilder {
+	b.orders = append(b.orders, quoteIdentifier(column))
+	return b
+}
+
+func (b *DeleteBuilder) OrderByDesc(column string) *DeleteBuilder {
+	b.orders = append(b.orders, quoteIdentifier(column)+" DESC")
+	return b
+}
+
+func (b *DeleteBuilder) Limit(limit int64) *DeleteBuilder {
+	b.limit = limit
+	return b
+}
+
+func (b *DeleteBuilder) Build() (string, []any, error) {
+	if b.table == "" {
+		return "", nil, ErrNoTable
+	}
+
+	var (
+		sb   strings.Builder
+		args []any
+	)
+
+	sb.WriteString("DELETE FROM ")
+	sb.WriteString(quoteIdentifier(b.table))
+
+	b.whereClause.write(&sb, &args)
+	b.orderClause.write(&sb)
+
+	return sb.String(), args, nil
+}
+
+// MaxAllowedPacket 서버의 max_allowed_packet. InsertBuilder.Chunks 에 넘길 값
+func (s *Handler) MaxAllowedPacket(ctx context.Context) (int, error) {
+	var result int
+	if err := s.sqlHandler.QueryRowContext(ctx, "SELECT @@max_allowed_packet").Scan(&result); err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,88 @@
+package xmysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWherePrecedence(t *testing.T) {
+	cases := []struct {
+		name  string
+		build func() (string, []any, error)
+		sql   string
+		args  []any
+	}{
+		{
+			name: "raw with or next to eq",
+			build: func() (string, []any, error) {
+				return Update("user").Set("gold", 0).Where(Raw("a = ? OR b = ?", 1, 2), Eq("uid", 7)).Build()
+			},
+			sql:  "UPDATE `user` SET `gold` = ? WHERE (a = ? OR b = ?) AND `uid` = ?",
+			args: []any{0, 1, 2, 7},
+		},
+		{
+			name: "single raw is not wrapped",
+			build: func() (string, []any, error) {
+				return DeleteFrom("user").Where(Raw("a = 1 OR b = 2")).Build()
+			},
+			sql: "DELETE FROM `user` WHERE a = 1 OR b = 2",
+		},
+		{
+			name: "single element or keeps raw wrapped",
+			build: func() (string, []any, error) {
+				return DeleteFrom("user").Where(Or(Raw("a = 1 OR b = 2")), Eq("uid", 7)).Build()
+			},
+			sql:  "DELETE FROM `user` WHERE (a = 1 OR b = 2) AND `uid` = ?",
+			args: []any{7},
+		},
+		{
+			name: "or inside and",
+			build: func() (string, []any, error) {
+				return Select("id").From("user").Where(Eq("server_id", 3), Or(Gte("level", 10), In("grade", "gold", "vip"))).Build()
+			},
+			sql:  "SELECT `id` FROM `user` WHERE `server_id` = ? AND (`level` >= ? OR `grade` IN (?, ?))",
+			args: []any{3, 10, "gold", "vip"},
+		},
+		{
+			name: "nested and or raw",
+			build: func() (string, []any, error) {
+				cond := Or(And(Eq("a", 1), Raw("b = ? OR c = ?", 2, 3)), And(Eq("d", 4), Not(Eq("e", 5))))
+				return Select().From("t").Where(cond, Eq("uid", 6)).Build()
+			},
+			sql:  "SELECT * FROM `t` WHERE ((`a` = ? AND (b = ? OR c = ?)) OR (`d` = ? AND NOT (`e` = ?))) AND `uid` = ?",
+			args: []any{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name: "empty and or",
+			build: func() (string, []any, error) {
+				return Select().From("t").Where(And(), Or()).Build()
+			},
+			sql: "SELECT * FROM `t` WHERE 1 = 1 AND 1 = 0",
+		},
+		{
+			name: "having wraps raw",
+			build: func() (string, []any, error) {
+				return Select("level").From("user").GroupBy("level").Having(Raw("COUNT(*) > ? OR MAX(gold) > ?", 1, 2), Raw("level > ?", 3)).Build()
+			},
+			sql:  "SELECT `level` FROM `user` GROUP BY `level` HAVING (COUNT(*) > ? OR MAX(gold) > ?) AND (level > ?)",
+			args: []any{1, 2, 3},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sqlString, args, err := c.build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sqlString != c.sql {
+				t.Errorf("sql\n got: %s\nwant: %s", sqlString, c.sql)
+			}
+			if len(args) != 0 || len(c.args) != 0 {
+				if !reflect.DeepEqual(args, c.args) {
+					t.Errorf("args got %v, want %v", args, c.args)
+				}
+			}
+		})
+	}
+}
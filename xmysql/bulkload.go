@@ -0,0 +1,245 @@
+package xmysql
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+/*
+## 사용법 ##
+type Item struct {
+	ID     int64 `db:"id"`			// auto increment 라서 Columns 에서 제외
+	UID    int64 `db:"uid"`
+	ItemID int32 `db:"item_id"`
+	Count  int32 `db:"count"`
+}
+
+loaded, err := xmysql.BulkLoadSlice(ctx, handler, xmysql.BulkLoadOptions{
+	Table:                "item",
+	Columns:              []string{"uid", "item_id", "count"},
+	OnDuplicateKeyUpdate: []string{"count"},
+	OnProgress: func(loaded int64) {
+		xlog.Info("item: %d rows loaded", loaded)
+	},
+}, items)
+*/
+
+const defaultChunkRows = 1000
+
+// BulkLoadOptions Columns 가 비어있으면 구조체의 모든 컬럼 (db 태그, 없으면 snake_case 필드 이름)
+// MaxPacket 이 0 이면 서버의 max_allowed_packet 을 조회해서 사용
+type BulkLoadOptions struct {
+	Table                string
+	Columns              []string
+	ChunkRows            int
+	MaxPacket            int
+	Ignore               bool
+	OnDuplicateKeyUpdate []string
+	OnProgress           func(loaded int64)
+}
+
+type insertColumn struct {
+	name  string
+	index []int
+}
+
+// BulkLoadSlice rows 를 BulkLoad 로 넣음
+func BulkLoadSlice[T any](ctx context.Context, handler Querier, opts BulkLoadOptions, rows []T) (int64, error) {
+	return BulkLoad(ctx, handler, opts, slices.Values(rows))
+}
+
+// BulkLoad ChunkRows (기본 1000) 행씩 multi-row INSERT 를 만들어 청크마다 트랜잭션 하나로 넣음
+// 실패하면 그 청크만 롤백되고, 그때까지 커밋된 행 수와 에러를 리턴함. T 는 구조체나 구조체 포인터
+func BulkLoad[T any](ctx context.Context, handler Querier, opts BulkLoadOptions, rows iter.Seq[T]) (int64, error) {
+	t := reflect.TypeFor[T]()
+	isPointer := t.Kind() == reflect.Pointer
+	if isPointer {
+		t = t.Elem()
+	}
+	if !isStructTarget(t) {
+		return 0, fmt.Errorf("xmysql: BulkLoad needs a struct type, got %s", t)
+	}
+
+	columns, err := bulkColumns(t, opts.Columns)
+	if err != nil {
+		return 0, err
+	}
+
+	maxPacket := opts.MaxPacket
+	if maxPacket <= 0 {
+		if maxPacket, err = handler.MaxAllowedPacket(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	chunkRows := opts.ChunkRows
+	if chunkRows <= 0 {
+		chunkRows = defaultChunkRows
+	}
+
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.name
+	}
+
+	var (
+		loaded int64
+		chunk  [][]any
+	)
+
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		builder := InsertInto(opts.Table).Columns(names...).OnDuplicateKeyUpdate(opts.OnDuplicateKeyUpdate...)
+		if opts.Ignore {
+			builder.Ignore()
+		}
+		for _, values := range chunk {
+			builder.Values(values...)
+		}
+
+		statements, err := builder.Chunks(maxPacket)
+		if err != nil {
+			return err
+		}
+
+		if err := handler.ExecuteBatch(ctx, statements...); err != nil {
+			return err
+		}
+
+		loaded += int64(len(chunk))
+		chunk = chunk[:0]
+
+		if opts.OnProgress != nil {
+			opts.OnProgress(loaded)
+		}
+
+		return nil
+	}
+
+	for row := range rows {
+		v := reflect.ValueOf(&row).Elem()
+		if isPointer {
+			if v.IsNil() {
+				return loaded, fmt.Errorf("xmysql: BulkLoad row %d is nil", loaded+int64(len(chunk)))
+			}
+			v = v.Elem()
+		}
+
+		values := make([]any, len(columns))
+		for i, column := range columns {
+			values[i] = columnValue(v, column.index)
+		}
+		chunk = append(chunk, values)
+
+		if len(chunk) >= chunkRows {
+			if err := flush(); err != nil {
+				return loaded, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return loaded, err
+	}
+
+	return loaded, nil
+}
+
+// bulkColumns names 가 있으면 그 순서대로 구조체 필드를 찾고, 없으면 구조체의 모든 컬럼을 필드 순서대로
+func bulkColumns(t reflect.Type, names []string) ([]insertColumn, error) {
+	if len(names) == 0 {
+		result := orderedColumns(t, nil, make(map[string]bool))
+		if len(result) == 0 {
+			return nil, fmt.Errorf("xmysql: %s has no columns to insert", t)
+		}
+		return result, nil
+	}
+
+	fields := structFields(t)
+	result := make([]insertColumn, len(names))
+	for i, name := range names {
+		field, ok := fields[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("xmysql: %s has no field for column %q", t, name)
+		}
+		result[i] = insertColumn{name: name, index: field.index}
+	}
+
+	return result, nil
+}
+
+// orderedColumns collectFields 와 같은 규칙이지만 필드마다 이름 하나만 씀. 바깥 구조체의 컬럼이 우선함
+func orderedColumns(t reflect.Type, parent []int, seen map[string]bool) []insertColumn {
+	var (
+		result   []insertColumn
+		embedded []reflect.StructField
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("db")
+		if tag == "-" {
+			continue
+		}
+
+		index := append(append([]int(nil), parent...), i)
+
+		if f.Anonymous && !hasTag {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if isStructTarget(ft) {
+				f.Index = index
+				embedded = append(embedded, f)
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = toSnakeCase(f.Name)
+		}
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		result = append(result, insertColumn{name: name, index: index})
+	}
+
+	for _, f := range embedded {
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		result = append(result, orderedColumns(ft, f.Index, seen)...)
+	}
+
+	return result
+}
+
+// columnValue 임베디드 포인터가 nil 이면 NULL
+func columnValue(v reflect.Value, index []int) any {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v.Interface()
+}
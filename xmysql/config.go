@@ -0,0 +1,91 @@
+package xmysql
+
+import (
+	"crypto/tls"
+	"database/sql"
+	"github.com/go-sql-driver/mysql"
+	"github.com/newbiediver/golib/scheduler"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Config 0 인 값은 database/sql 기본값을 그대로 사용함
+// TLS 가 nil 이 아니면 TLS 로 연결하고, Params 는 DSN 에 추가로 붙는 파라미터 (예: "time_zone": "'+09:00'")
+type Config struct {
+	Server          string
+	UID             string
+	PWD             string
+	Source          string
+	Port            int
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	TLS             *tls.Config
+	Params          map[string]string
+}
+
+var (
+	backgroundOnce sync.Once
+)
+
+// NewHandlerWithConfig 새연결. 연결 확인에 실패하면 에러를 리턴
+func NewHandlerWithConfig(cfg Config) (*Handler, error) {
+	mysqlConfig := mysql.NewConfig()
+	mysqlConfig.User = cfg.UID
+	mysqlConfig.Passwd = cfg.PWD
+	mysqlConfig.Net = "tcp"
+	mysqlConfig.Addr = cfg.Server + ":" + strconv.Itoa(cfg.Port)
+	mysqlConfig.DBName = cfg.Source
+	mysqlConfig.MultiStatements = true
+	mysqlConfig.ParseTime = true
+	mysqlConfig.TLS = cfg.TLS
+	mysqlConfig.Params = map[string]string{"charset": "utf8mb4"}
+	for k, v := range cfg.Params {
+		mysqlConfig.Params[k] = v
+	}
+
+	connector, err := mysql.NewConnector(mysqlConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	dbHandler := sql.OpenDB(connector)
+	if cfg.MaxOpenConns > 0 {
+		dbHandler.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		dbHandler.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		dbHandler.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if cfg.ConnMaxIdleTime > 0 {
+		dbHandler.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+
+	if err := dbHandler.Ping(); err != nil {
+		_ = dbHandler.Close()
+		return nil, err
+	}
+
+	newHandler := new(Handler)
+	newHandler.sqlHandler = dbHandler
+	newHandler.health.healthy.Store(true)
+
+	startBackground()
+
+	return newHandler, nil
+}
+
+// startBackground 모니터링 중인 모든 핸들러를 30초마다 확인하는 백그라운드 스케쥴러
+func startBackground() {
+	backgroundOnce.Do(func() {
+		backgroundObject = new(scheduler.Handler)
+		backgroundObject.Run(scheduler.PriorityVerySlow)
+
+		obj := scheduler.CreateObjectByInterval(30000, checkHealth).SetName("xmysql-health")
+		backgroundObject.NewObject(obj)
+	})
+}
@@ -1,12 +1,16 @@
 package xmysql
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
-	"fmt"
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
+	"github.com/newbiediver/golib/container"
 	"github.com/newbiediver/golib/exception"
 	"github.com/newbiediver/golib/scheduler"
+	"sync"
+	"time"
 )
 
 /*
@@ -54,6 +58,22 @@ func testCallDB() {
 	handler.Query(&newQuery)
 }
 
+2-1. 파라미터 쿼리 (SqlString 에 값을 직접 넣지 말고 ? 와 Args 를 사용)
+func testCallDB(sid int) {
+	handler := xmysql.GetHandler("main")
+	handler.EnableStatementCache(256)		// 선택: 같은 SQL 은 prepared statement 를 재사용
+
+	newQuery := xmysql.QueryExecutor{
+		SqlString: "SELECT * FROM someTable WHERE sid = ?;",
+		Args:      []any{sid},
+		OnQuery:   ...,
+		OnError:   ...,
+	}
+
+	handler.Query(&newQuery)
+	handler.Execute("UPDATE someTable SET str = ? WHERE sid = ?;", nil, onError, "테스트", sid)
+}
+
 3. 트랜잭션
 func testCallDB() {
 	handler := xmysql.GetHandler("main")
@@ -91,11 +111,27 @@ func testCallDB() {
 */
 
 type Handler struct {
-	sqlHandler *sql.DB
+	sqlHandler   *sql.DB
+	stmtLock     sync.Mutex
+	stmtCache    *container.LRUCache[string, *cachedStmt]
+	queryTimeout time.Duration
+	inflight     sync.WaitGroup
+	closeLock    sync.RWMutex
+	closed       bool
+	health       healthState
+	hooks        queryHooks
+}
+
+// cachedStmt 사용 중인 statement 가 캐시에서 밀려나도 바로 닫히지 않도록 참조 수를 셈. refs, evicted 는 stmtLock 으로 보호
+type cachedStmt struct {
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
 }
 
 type RecordSet struct {
 	curRows *sql.Rows
+	cancel  context.CancelFunc
 }
 
 type ExecCallback func(int64, int64)
@@ -106,73 +142,353 @@ type RollbackCallback func(error)
 
 type QueryExecutor struct {
 	SqlString string
+	Args      []any
 	OnQuery   QueryCallback
 	OnError   ErrorCallback
 }
 
 var (
+	ErrHandlerClosed = errors.New("xmysql: handler is closed")
+)
+
+var (
+	handlersLock     sync.RWMutex
 	managedHandlers  map[string]*Handler
 	backgroundObject *scheduler.Handler
 )
 
 // NewHandler 새연결
 func NewHandler(server, uid, pwd, source string, port, io int) (*Handler, error) {
-	connectionString := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&multiStatements=true&parseTime=true",
-		uid, pwd, server, port, source)
+	return NewHandlerWithConfig(Config{
+		Server:       server,
+		UID:          uid,
+		PWD:          pwd,
+		Source:       source,
+		Port:         port,
+		MaxIdleConns: io,
+	})
+}
 
-	dbHandler, err := sql.Open("mysql", connectionString)
-	if err != nil {
-		return nil, err
+// KeepHandler 연결을 보관하고자 할 때
+func KeepHandler(name string, newHandler *Handler) {
+	handlersLock.Lock()
+	if managedHandlers == nil {
+		managedHandlers = make(map[string]*Handler)
+	}
+	managedHandlers[name] = newHandler
+	handlersLock.Unlock()
+
+	MonitorHandler(name, newHandler)
+}
+
+func FlushHandlers() {
+	backgroundObject.Stop()
+	for _, handler := range keptHandlers() {
+		_ = handler.sqlHandler.Close()
+	}
+}
+
+func keptHandlers() []*Handler {
+	handlersLock.RLock()
+	defer handlersLock.RUnlock()
+
+	result := make([]*Handler, 0, len(managedHandlers))
+	for _, handler := range managedHandlers {
+		result = append(result, handler)
+	}
+
+	return result
+}
+
+// FlushHandlersContext 보관된 핸들러마다 진행중인 비동기 쿼리를 ctx 만큼 기다린 뒤 닫음
+func FlushHandlersContext(ctx context.Context) error {
+	var result error
+
+	_ = backgroundObject.StopContext(ctx)
+	for _, handler := range keptHandlers() {
+		if err := handler.Close(ctx); err != nil && result == nil {
+			result = err
+		}
 	}
 
-	dbHandler.SetMaxIdleConns(io)
-	err = dbHandler.Ping()
+	return result
+}
+
+func GetHandler(name string) *Handler {
+	handlersLock.RLock()
+	defer handlersLock.RUnlock()
+
+	return managedHandlers[name]
+}
+
+// EnableStatementCache 파라미터가 있는 쿼리를 SQL 문자열 단위로 prepare 해서 size 개까지 재사용
+// 밀려난 statement 는 사용 중인 쿼리가 모두 끝난 뒤 닫히고, 연결이 끊겼거나 이미 닫혀 실패한 statement 는 다시 prepare 해서 한번 재시도함
+func (s *Handler) EnableStatementCache(size int) {
+	defer s.stmtLock.Unlock()
+
+	s.stmtLock.Lock()
+	if s.stmtCache != nil {
+		s.stmtCache.Purge()
+	}
+
+	// OnEvict 는 항상 stmtLock 을 잡은 상태에서 불림
+	s.stmtCache = container.NewLRUCache(container.LRUOptions[string, *cachedStmt]{
+		MaxEntries: size,
+		OnEvict: func(_ string, cached *cachedStmt, _ container.EvictReason) {
+			cached.evicted = true
+			if cached.refs == 0 {
+				_ = cached.stmt.Close()
+			}
+		},
+	})
+}
+
+// acquireStatement prepare 는 stmtLock 밖에서 함. 그 사이 다른 goroutine 이 먼저 넣었으면 내 것을 닫고 그쪽을 씀
+func (s *Handler) acquireStatement(ctx context.Context, sqlString string) (*cachedStmt, error) {
+	if cached := s.cachedStatement(sqlString); cached != nil {
+		return cached, nil
+	}
+
+	stmt, err := s.sqlHandler.PrepareContext(ctx, sqlString)
 	if err != nil {
 		return nil, err
 	}
 
-	newHandler := new(Handler)
-	newHandler.sqlHandler = dbHandler
+	s.stmtLock.Lock()
+	if cached, ok := s.stmtCache.Get(sqlString); ok {
+		cached.refs++
+		s.stmtLock.Unlock()
 
-	if backgroundObject == nil {
-		backgroundObject = new(scheduler.Handler)
-		backgroundObject.Run(scheduler.PriorityVerySlow)
+		_ = stmt.Close()
+		return cached, nil
+	}
 
-		obj := scheduler.CreateObjectByInterval(30000, func() {
-			if err := newHandler.sqlHandler.Ping(); err != nil {
-				fmt.Printf("SQL connections look like disconnected: %s", err.Error())
-			}
-		})
+	cached := &cachedStmt{stmt: stmt, refs: 1}
+	s.stmtCache.Set(sqlString, cached)
+	s.stmtLock.Unlock()
+
+	return cached, nil
+}
 
-		backgroundObject.NewObject(obj)
+func (s *Handler) cachedStatement(sqlString string) *cachedStmt {
+	defer s.stmtLock.Unlock()
+
+	s.stmtLock.Lock()
+	cached, ok := s.stmtCache.Get(sqlString)
+	if !ok {
+		return nil
 	}
 
-	return newHandler, nil
+	cached.refs++
+	return cached
 }
 
-// KeepHandler 연결을 보관하고자 할 때
-func KeepHandler(name string, newHandler *Handler) {
-	if managedHandlers == nil {
-		managedHandlers = make(map[string]*Handler)
+func (s *Handler) releaseStatement(cached *cachedStmt) {
+	defer s.stmtLock.Unlock()
+
+	s.stmtLock.Lock()
+	cached.refs--
+	if cached.evicted && cached.refs == 0 {
+		_ = cached.stmt.Close()
 	}
-	managedHandlers[name] = newHandler
 }
 
-func FlushHandlers() {
-	backgroundObject.Stop()
-	for _, handler := range managedHandlers {
-		_ = handler.sqlHandler.Close()
+// dropStatement 실패한 statement 가 아직 캐시에 있으면 빼서 다음 사용자가 새로 prepare 하게 함
+func (s *Handler) dropStatement(sqlString string, cached *cachedStmt) {
+	defer s.stmtLock.Unlock()
+
+	s.stmtLock.Lock()
+	if current, ok := s.stmtCache.Peek(sqlString); ok && current == cached {
+		s.stmtCache.Delete(sqlString)
 	}
 }
 
-func GetHandler(name string) *Handler {
-	return managedHandlers[name]
+// withStatement 캐시된 statement 의 참조를 잡은 채로 fn 을 실행함
+// 연결이 끊겼거나 statement 가 닫혀 실패하면 캐시에서 빼고 다시 prepare 해서 한번 재시도함
+func (s *Handler) withStatement(ctx context.Context, sqlString string, fn func(stmt *sql.Stmt) error) error {
+	for retried := false; ; retried = true {
+		cached, err := s.acquireStatement(ctx, sqlString)
+		if err != nil {
+			return err
+		}
+
+		err = fn(cached.stmt)
+		s.releaseStatement(cached)
+
+		if err == nil || retried || !(isBadConnection(err) || isStatementClosed(err)) {
+			return err
+		}
+		s.dropStatement(sqlString, cached)
+	}
+}
+
+func isBadConnection(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) || errors.Is(err, sql.ErrConnDone)
+}
+
+// isStatementClosed database/sql 은 닫힌 statement 에러를 export 하지 않으므로 메시지로 비교함
+func isStatementClosed(err error) bool {
+	return err != nil && err.Error() == "sql: statement is closed"
+}
+
+// useStatement 캐시가 켜져 있고 파라미터가 있는 쿼리만 prepared statement 로 실행함
+func (s *Handler) useStatement(args []any) bool {
+	return s.stmtCache != nil && len(args) > 0
+}
+
+func (s *Handler) query(ctx context.Context, sqlString string, args []any) (rows *sql.Rows, err error) {
+	ctx, finish := s.startQuery(ctx, QueryKindQuery, false, sqlString, args)
+	defer func() { finish(nil, err) }()
+
+	if !s.useStatement(args) {
+		return s.sqlHandler.QueryContext(ctx, sqlString, args...)
+	}
+
+	err = s.withStatement(ctx, sqlString, func(stmt *sql.Stmt) error {
+		var queryErr error
+		rows, queryErr = stmt.QueryContext(ctx, args...)
+		return queryErr
+	})
+
+	return rows, err
+}
+
+func (s *Handler) exec(ctx context.Context, sqlString string, args []any) (result sql.Result, err error) {
+	ctx, finish := s.startQuery(ctx, QueryKindExec, false, sqlString, args)
+	defer func() { finish(result, err) }()
+
+	if !s.useStatement(args) {
+		return s.sqlHandler.ExecContext(ctx, sqlString, args...)
+	}
+
+	err = s.withStatement(ctx, sqlString, func(stmt *sql.Stmt) error {
+		var execErr error
+		result, execErr = stmt.ExecContext(ctx, args...)
+		return execErr
+	})
+
+	return result, err
+}
+
+func (s *Handler) txQuery(ctx context.Context, tx *sql.Tx, sqlString string, args []any) (rows *sql.Rows, err error) {
+	ctx, finish := s.startQuery(ctx, QueryKindQuery, true, sqlString, args)
+	defer func() { finish(nil, err) }()
+
+	if !s.useStatement(args) {
+		return tx.QueryContext(ctx, sqlString, args...)
+	}
+
+	err = s.withStatement(ctx, sqlString, func(stmt *sql.Stmt) error {
+		var queryErr error
+		rows, queryErr = tx.StmtContext(ctx, stmt).QueryContext(ctx, args...)
+		return queryErr
+	})
+
+	return rows, err
 }
 
-func (s *Handler) SyncQuery(sqlString string) (*RecordSet, error) {
-	result := RecordSet{}
-	rows, err := s.sqlHandler.Query(sqlString)
+// SetQueryTimeout ctx 에 더 짧은 데드라인이 없으면 모든 쿼리에 적용할 기본 타임아웃 (0 이면 없음)
+func (s *Handler) SetQueryTimeout(timeout time.Duration) {
+	s.queryTimeout = timeout
+}
+
+func (s *Handler) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= s.queryTimeout {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, s.queryTimeout)
+}
+
+// begin 비동기 작업 시작. Close 가 호출된 뒤면 false
+func (s *Handler) begin() bool {
+	s.closeLock.RLock()
+	defer s.closeLock.RUnlock()
+
+	if s.closed {
+		return false
+	}
+
+	s.inflight.Add(1)
+	return true
+}
+
+// Conn 풀에서 커넥션 하나를 점유해서 리턴. 세션 변수나 GET_LOCK 처럼 커넥션 단위 상태가 필요할 때 사용하고, 다 쓰면 Close 해야 함
+func (s *Handler) Conn(ctx context.Context) (*sql.Conn, error) {
+	if s.isClosed() {
+		return nil, ErrHandlerClosed
+	}
+
+	return s.sqlHandler.Conn(ctx)
+}
+
+func (s *Handler) isClosed() bool {
+	s.closeLock.RLock()
+	defer s.closeLock.RUnlock()
+
+	return s.closed
+}
+
+// Close 새 쿼리를 막고 진행중인 비동기 쿼리가 끝날 때까지 ctx 만큼 기다린 뒤 풀을 닫음
+// ctx 가 먼저 끝나면 기다리지 않고 닫은 뒤 ctx.Err() 를 리턴
+func (s *Handler) Close(ctx context.Context) error {
+	s.closeLock.Lock()
+	if s.closed {
+		s.closeLock.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.closeLock.Unlock()
+
+	UnmonitorHandler(s)
+
+	var result error
+
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		result = ctx.Err()
+	}
+
+	s.stmtLock.Lock()
+	if s.stmtCache != nil {
+		s.stmtCache.Purge()
+	}
+	s.stmtLock.Unlock()
+
+	if err := s.sqlHandler.Close(); err != nil && result == nil {
+		result = err
+	}
+
+	return result
+}
+
+func (s *Handler) SyncQuery(sqlString string, args ...any) (*RecordSet, error) {
+	return s.SyncQueryContext(context.Background(), sqlString, args...)
+}
+
+// SyncQueryContext 기본 타임아웃은 RecordSet.Close 때까지 유지됨
+func (s *Handler) SyncQueryContext(ctx context.Context, sqlString string, args ...any) (*RecordSet, error) {
+	if s.isClosed() {
+		return nil, ErrHandlerClosed
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+
+	result := RecordSet{cancel: cancel}
+	rows, err := s.query(ctx, sqlString, args)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
@@ -183,8 +499,48 @@ func (s *Handler) SyncQuery(sqlString string) (*RecordSet, error) {
 	return &result, nil
 }
 
-func (s *Handler) Execute(queryString string, execCallback ExecCallback, errCallback ErrorCallback) {
+func (s *Handler) SyncExecute(sqlString string, args ...any) (sql.Result, error) {
+	return s.SyncExecuteContext(context.Background(), sqlString, args...)
+}
+
+// SyncExecuteContext 결과를 콜백 대신 바로 리턴하는 ExecuteContext
+func (s *Handler) SyncExecuteContext(ctx context.Context, sqlString string, args ...any) (sql.Result, error) {
+	if s.isClosed() {
+		return nil, ErrHandlerClosed
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return s.exec(ctx, sqlString, args)
+}
+
+// ExecuteBatch statements 를 한 트랜잭션에서 순서대로 실행. 데드락이면 WithTx 처럼 처음부터 다시 시도함
+func (s *Handler) ExecuteBatch(ctx context.Context, statements ...Statement) error {
+	return s.WithTx(ctx, func(tx *Tx) error {
+		for _, statement := range statements {
+			if _, err := tx.Execute(ctx, statement.SQL, statement.Args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Handler) Execute(queryString string, execCallback ExecCallback, errCallback ErrorCallback, args ...any) {
+	s.ExecuteContext(context.Background(), queryString, execCallback, errCallback, args...)
+}
+
+func (s *Handler) ExecuteContext(ctx context.Context, queryString string, execCallback ExecCallback, errCallback ErrorCallback, args ...any) {
+	if !s.begin() {
+		if errCallback != nil {
+			errCallback(ErrHandlerClosed)
+		}
+		return
+	}
+
 	go func() {
+		defer s.inflight.Done()
 		defer func() {
 			if rcv := recover(); rcv != nil {
 				if ex := exception.GetExceptionHandler(); ex != nil {
@@ -193,7 +549,10 @@ func (s *Handler) Execute(queryString string, execCallback ExecCallback, errCall
 			}
 		}()
 
-		r, err := s.sqlHandler.Exec(queryString)
+		ctx, cancel := s.withTimeout(ctx)
+		defer cancel()
+
+		r, err := s.exec(ctx, queryString, args)
 		if err != nil {
 			errCallback(err)
 			return
@@ -210,9 +569,26 @@ func (s *Handler) Execute(queryString string, execCallback ExecCallback, errCall
 
 // Query 단일 쿼리 호출
 func (s *Handler) Query(executor *QueryExecutor) {
+	s.QueryContext(context.Background(), executor)
+}
+
+// QueryContext ctx 가 취소되거나 기본 타임아웃이 지나면 쿼리가 중단되고 OnError 로 ctx 에러가 전달됨
+func (s *Handler) QueryContext(ctx context.Context, executor *QueryExecutor) {
+	if !s.begin() {
+		if executor.OnError != nil {
+			executor.OnError(ErrHandlerClosed)
+		}
+		return
+	}
+
 	go func() {
+		defer s.inflight.Done()
+
+		ctx, cancel := s.withTimeout(ctx)
+		defer cancel()
+
 		result := RecordSet{}
-		rows, err := s.sqlHandler.Query(executor.SqlString)
+		rows, err := s.query(ctx, executor.SqlString, executor.Args)
 
 		defer func() {
 			if rows != nil {
@@ -255,65 +631,44 @@ func (s *Handler) Query(executor *QueryExecutor) {
 
 // Transaction 트랜잭션을 위한 쿼리 호출 (주의: procedure 를 사용할 경우 procedure 내에서 transaction 처리를 하면 안됨)
 func (s *Handler) Transaction(onCommit CommitCallback, onRollback RollbackCallback, queries ...*QueryExecutor) {
+	s.TransactionContext(context.Background(), onCommit, onRollback, queries...)
+}
+
+// TransactionContext 기본 타임아웃은 트랜잭션 전체에 적용됨
+// Begin 이나 Commit 이 실패해도 onRollback 으로 에러가 전달됨
+func (s *Handler) TransactionContext(ctx context.Context, onCommit CommitCallback, onRollback RollbackCallback, queries ...*QueryExecutor) {
 	if onCommit == nil || onRollback == nil {
 		panic("[DBTransaction] Commit or Rollback callback is nil")
 	}
+	if !s.begin() {
+		onRollback(ErrHandlerClosed)
+		return
+	}
+
 	go func() {
-		_, _ = s.sqlHandler.Exec("SET AUTOCOMMIT = 0;")
-		defer func() {
-			_, _ = s.sqlHandler.Exec("SET AUTOCOMMIT = 1;")
-		}()
+		defer s.inflight.Done()
 
-		tx, err := s.sqlHandler.Begin()
+		ctx, cancel := s.withTimeout(ctx)
+		defer cancel()
+
+		tx, err := s.BeginTx(ctx, nil)
 		if err != nil {
-			panic(err)
+			onRollback(err)
+			return
 		}
 
-		defer func() {
-			if r := recover(); r != nil {
-				_ = tx.Rollback()
-
-				switch r.(type) {
-				case string:
-					onRollback(errors.New(r.(string)))
-				case error:
-					onRollback(r.(error))
-				default:
-					onRollback(errors.New("unknown error"))
-				}
-				fmt.Println(r)
-			}
-		}()
-
-		for _, executor := range queries {
-			result := RecordSet{}
-			rows, err := tx.Query(executor.SqlString)
-			if err != nil {
-				if executor.OnError != nil {
-					executor.OnError(err)
-				}
-				panic(err)
-			}
-
-			if rows != nil {
-				result.curRows = rows
-			}
-
-			err = executor.OnQuery(&result)
-
-			if rows != nil {
-				_ = rows.Close()
-			}
+		if err := tx.runExecutors(ctx, queries); err != nil {
+			_ = tx.Rollback()
+			onRollback(err)
+			return
+		}
 
-			if err != nil {
-				if executor.OnError != nil {
-					executor.OnError(err)
-				}
-				panic(err)
-			}
+		if err := tx.Commit(); err != nil {
+			_ = tx.Rollback()
+			onRollback(err)
+			return
 		}
 
-		_ = tx.Commit()
 		onCommit()
 	}()
 }
@@ -343,4 +698,7 @@ func (rs *RecordSet) Close() {
 	if rs.curRows != nil {
 		_ = rs.curRows.Close()
 	}
+	if rs.cancel != nil {
+		rs.cancel()
+	}
 }
@@ -0,0 +1,173 @@
+package xmysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	healthCheckTimeout = 5 * time.Second
+)
+
+// HealthEvent 핸들러의 상태가 바뀌었을 때 훅으로 전달됨
+type HealthEvent struct {
+	Name    string
+	Handler *Handler
+	Healthy bool
+	Err     error
+	Stats   sql.DBStats
+}
+
+type HealthHook func(event HealthEvent)
+
+// HealthStatus readiness probe 용 상태 스냅샷
+type HealthStatus struct {
+	Name      string      `json:"name"`
+	Healthy   bool        `json:"healthy"`
+	LastCheck time.Time   `json:"lastCheck"`
+	LastError string      `json:"lastError,omitempty"`
+	Stats     sql.DBStats `json:"stats"`
+}
+
+type healthState struct {
+	healthy   atomic.Bool
+	lock      sync.Mutex
+	name      string
+	lastCheck time.Time
+	lastError error
+}
+
+var (
+	monitorLock       sync.Mutex
+	monitoredHandlers = make(map[*Handler]struct{})
+	healthHook        HealthHook
+)
+
+// SetHealthHook 모니터링 중인 핸들러가 healthy <-> unhealthy 로 바뀔 때 호출됨
+func SetHealthHook(hook HealthHook) {
+	monitorLock.Lock()
+	healthHook = hook
+	monitorLock.Unlock()
+}
+
+// MonitorHandler 보관하지 않는 핸들러(샤드, 레플리카 등)도 주기적으로 상태를 확인하도록 등록. KeepHandler 는 자동으로 등록함
+func MonitorHandler(name string, handler *Handler) {
+	handler.health.lock.Lock()
+	handler.health.name = name
+	handler.health.lock.Unlock()
+
+	monitorLock.Lock()
+	monitoredHandlers[handler] = struct{}{}
+	monitorLock.Unlock()
+}
+
+// monitorHandlerOnce 이미 등록된 핸들러는 이름을 바꾸지 않음
+func monitorHandlerOnce(name string, handler *Handler) {
+	monitorLock.Lock()
+	_, monitored := monitoredHandlers[handler]
+	monitorLock.Unlock()
+
+	if !monitored {
+		MonitorHandler(name, handler)
+	}
+}
+
+func UnmonitorHandler(handler *Handler) {
+	monitorLock.Lock()
+	delete(monitoredHandlers, handler)
+	monitorLock.Unlock()
+}
+
+// HealthStatuses 모니터링 중인 모든 핸들러의 상태
+func HealthStatuses() []HealthStatus {
+	monitorLock.Lock()
+	handlers := make([]*Handler, 0, len(monitoredHandlers))
+	for handler := range monitoredHandlers {
+		handlers = append(handlers, handler)
+	}
+	monitorLock.Unlock()
+
+	result := make([]HealthStatus, 0, len(handlers))
+	for _, handler := range handlers {
+		result = append(result, handler.HealthStatus())
+	}
+
+	return result
+}
+
+func (s *Handler) Stats() sql.DBStats {
+	return s.sqlHandler.Stats()
+}
+
+// Healthy 마지막 상태 확인 결과
+func (s *Handler) Healthy() bool {
+	return s.health.healthy.Load()
+}
+
+func (s *Handler) HealthStatus() HealthStatus {
+	s.health.lock.Lock()
+	defer s.health.lock.Unlock()
+
+	result := HealthStatus{
+		Name:      s.health.name,
+		Healthy:   s.health.healthy.Load(),
+		LastCheck: s.health.lastCheck,
+		Stats:     s.sqlHandler.Stats(),
+	}
+	if s.health.lastError != nil {
+		result.LastError = s.health.lastError.Error()
+	}
+
+	return result
+}
+
+// HealthCheck 바로 ping 해서 상태를 갱신하고, 상태가 바뀌었으면 훅을 호출함
+func (s *Handler) HealthCheck(ctx context.Context) error {
+	err := s.sqlHandler.PingContext(ctx)
+	healthy := err == nil
+
+	s.health.lock.Lock()
+	s.health.lastCheck = time.Now()
+	s.health.lastError = err
+	name := s.health.name
+	s.health.lock.Unlock()
+
+	if s.health.healthy.Swap(healthy) != healthy {
+		monitorLock.Lock()
+		hook := healthHook
+		monitorLock.Unlock()
+
+		if hook != nil {
+			hook(HealthEvent{
+				Name:    name,
+				Handler: s,
+				Healthy: healthy,
+				Err:     err,
+				Stats:   s.sqlHandler.Stats(),
+			})
+		} else if !healthy {
+			fmt.Printf("SQL connections look like disconnected: %s\n", err.Error())
+		}
+	}
+
+	return err
+}
+
+func checkHealth() {
+	monitorLock.Lock()
+	handlers := make([]*Handler, 0, len(monitoredHandlers))
+	for handler := range monitoredHandlers {
+		handlers = append(handlers, handler)
+	}
+	monitorLock.Unlock()
+
+	for _, handler := range handlers {
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+		_ = handler.HealthCheck(ctx)
+		cancel()
+	}
+}
@@ -0,0 +1,182 @@
+package xmysql
+
+import (
+	"context"
+	"database/sql"
+	"github.com/newbiediver/golib/xlog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"sync/atomic"
+	"time"
+)
+
+/*
+## 사용법 ##
+handler.AddQueryHook(xmysql.NewSlowQueryLogger(200 * time.Millisecond))
+handler.AddQueryHook(xmysql.NewTracingHook(otel.Tracer("game-db")))
+*/
+
+type QueryKind string
+
+const (
+	QueryKindQuery QueryKind = "query"
+	QueryKindExec  QueryKind = "exec"
+)
+
+// QueryEvent 훅에 전달되는 쿼리 정보. Duration 은 결과를 받기 시작할 때까지의 시간이라 RecordSet 을 읽는 시간은 포함되지 않음
+// RowsAffected 는 exec 일 때만 채워지고 query 는 -1
+type QueryEvent struct {
+	Kind         QueryKind
+	SQL          string
+	Args         []any
+	InTx         bool
+	Start        time.Time
+	Duration     time.Duration
+	RowsAffected int64
+	Err          error
+}
+
+// QueryHook Query, SyncQuery, Execute, Transaction 과 Tx 의 모든 문장 전후에 호출됨
+// BeforeQuery 가 리턴한 ctx 가 쿼리와 AfterQuery 에 사용됨
+type QueryHook interface {
+	BeforeQuery(ctx context.Context, event *QueryEvent) context.Context
+	AfterQuery(ctx context.Context, event *QueryEvent)
+}
+
+type queryHooks struct {
+	list atomic.Pointer[[]QueryHook]
+}
+
+// AddQueryHook 추가한 순서대로 BeforeQuery, 역순으로 AfterQuery 가 호출됨
+func (s *Handler) AddQueryHook(hook QueryHook) {
+	for {
+		old := s.hooks.list.Load()
+
+		var hooks []QueryHook
+		if old != nil {
+			hooks = append(hooks, *old...)
+		}
+		hooks = append(hooks, hook)
+
+		if s.hooks.list.CompareAndSwap(old, &hooks) {
+			return
+		}
+	}
+}
+
+func noopFinish(sql.Result, error) {}
+
+// startQuery 훅이 없으면 아무것도 하지 않음. 쿼리가 끝나면 리턴된 함수를 결과와 함께 호출해야 함
+func (s *Handler) startQuery(ctx context.Context, kind QueryKind, inTx bool, sqlString string, args []any) (context.Context, func(sql.Result, error)) {
+	list := s.hooks.list.Load()
+	if list == nil {
+		return ctx, noopFinish
+	}
+
+	hooks := *list
+	event := &QueryEvent{
+		Kind:         kind,
+		SQL:          sqlString,
+		Args:         args,
+		InTx:         inTx,
+		Start:        time.Now(),
+		RowsAffected: -1,
+	}
+
+	contexts := make([]context.Context, len(hooks))
+	for i, hook := range hooks {
+		contexts[i] = ctx
+		ctx = hook.BeforeQuery(ctx, event)
+	}
+
+	return ctx, func(result sql.Result, err error) {
+		event.Duration = time.Since(event.Start)
+		event.Err = err
+		if result != nil && err == nil {
+			if affected, err := result.RowsAffected(); err == nil {
+				event.RowsAffected = affected
+			}
+		}
+
+		for i := len(hooks) - 1; i >= 0; i-- {
+			hooks[i].AfterQuery(ctx, event)
+			ctx = contexts[i]
+		}
+	}
+}
+
+// SlowQueryLogger Threshold 이상 걸린 쿼리를 xlog.Warn 으로 남김. xlog.RunLogger 가 먼저 호출되어 있어야 함
+type SlowQueryLogger struct {
+	Threshold time.Duration
+	LogArgs   bool
+	MaxLength int
+}
+
+// NewSlowQueryLogger 인자는 개인정보가 섞일 수 있으므로 기본으로 남기지 않음. SQL 은 1024 자까지만 남김
+func NewSlowQueryLogger(threshold time.Duration) *SlowQueryLogger {
+	result := new(SlowQueryLogger)
+	result.Threshold = threshold
+	result.MaxLength = 1024
+
+	return result
+}
+
+func (l *SlowQueryLogger) BeforeQuery(ctx context.Context, _ *QueryEvent) context.Context {
+	return ctx
+}
+
+func (l *SlowQueryLogger) AfterQuery(_ context.Context, event *QueryEvent) {
+	if event.Duration < l.Threshold {
+		return
+	}
+
+	sqlString := event.SQL
+	if l.MaxLength > 0 && len(sqlString) > l.MaxLength {
+		sqlString = sqlString[:l.MaxLength] + "..."
+	}
+
+	if l.LogArgs {
+		xlog.Warn("[xmysql] slow %s (%s): %s args=%v err=%v", event.Kind, event.Duration, sqlString, event.Args, event.Err)
+	} else {
+		xlog.Warn("[xmysql] slow %s (%s): %s err=%v", event.Kind, event.Duration, sqlString, event.Err)
+	}
+}
+
+// TracingHook 쿼리마다 OpenTelemetry 스팬을 만듦. 인자는 남기지 않음
+type TracingHook struct {
+	tracer trace.Tracer
+}
+
+func NewTracingHook(tracer trace.Tracer) *TracingHook {
+	result := new(TracingHook)
+	result.tracer = tracer
+
+	return result
+}
+
+func (h *TracingHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	ctx, _ = h.tracer.Start(ctx, "xmysql."+string(event.Kind),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(event.Start),
+		trace.WithAttributes(
+			attribute.String("db.system", "mysql"),
+			attribute.String("db.statement", event.SQL),
+			attribute.Bool("db.in_transaction", event.InTx),
+		))
+
+	return ctx
+}
+
+func (h *TracingHook) AfterQuery(ctx context.Context, event *QueryEvent) {
+	span := trace.SpanFromContext(ctx)
+	if event.RowsAffected >= 0 {
+		span.SetAttributes(attribute.Int64("db.rows_affected", event.RowsAffected))
+	}
+	if event.Err != nil {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	}
+
+	span.End(trace.WithTimestamp(event.Start.Add(event.Duration)))
+}
@@ -0,0 +1,82 @@
+package xmysql
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/newbiediver/golib/scheduler"
+	"strings"
+	"time"
+)
+
+// JobStore scheduler.JobStore 의 mysql 구현. 테이블이 없으면 NewJobStore 에서 생성함
+type JobStore struct {
+	handler *Handler
+	table   string
+}
+
+func NewJobStore(handler *Handler, table string) (*JobStore, error) {
+	result := new(JobStore)
+	result.handler = handler
+	result.table = "`" + strings.ReplaceAll(table, "`", "``") + "`"
+
+	_, err := handler.sqlHandler.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	name VARCHAR(191) NOT NULL PRIMARY KEY,
+	kind VARCHAR(191) NOT NULL,
+	job_type VARCHAR(16) NOT NULL,
+	interval_ms BIGINT NOT NULL DEFAULT 0,
+	hour INT NOT NULL DEFAULT 0,
+	minute INT NOT NULL DEFAULT 0,
+	second INT NOT NULL DEFAULT 0,
+	next_fire DATETIME(6) NOT NULL,
+	args TEXT NULL
+)`, result.table))
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (j *JobStore) Save(record scheduler.JobRecord) error {
+	var args sql.NullString
+	if record.Args != nil {
+		args = sql.NullString{String: string(record.Args), Valid: true}
+	}
+
+	_, err := j.handler.sqlHandler.Exec(fmt.Sprintf("REPLACE INTO %s (name, kind, job_type, interval_ms, hour, minute, second, next_fire, args) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)", j.table),
+		record.Name, record.Kind, record.Type, record.Interval, record.Hour, record.Minute, record.Second, record.NextFire.In(time.UTC), args)
+	return err
+}
+
+func (j *JobStore) Delete(name string) error {
+	_, err := j.handler.sqlHandler.Exec(fmt.Sprintf("DELETE FROM %s WHERE name = ?", j.table), name)
+	return err
+}
+
+func (j *JobStore) Load() ([]scheduler.JobRecord, error) {
+	rows, err := j.handler.sqlHandler.Query(fmt.Sprintf("SELECT name, kind, job_type, interval_ms, hour, minute, second, next_fire, args FROM %s ORDER BY next_fire", j.table))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var result []scheduler.JobRecord
+	for rows.Next() {
+		var (
+			record scheduler.JobRecord
+			args   sql.NullString
+		)
+
+		if err := rows.Scan(&record.Name, &record.Kind, &record.Type, &record.Interval, &record.Hour, &record.Minute, &record.Second, &record.NextFire, &args); err != nil {
+			return nil, err
+		}
+
+		if args.Valid {
+			record.Args = []byte(args.String)
+		}
+		result = append(result, record)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,83 @@
+package xmysql
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"github.com/newbiediver/golib/scheduler"
+	"time"
+)
+
+// Locker scheduler.Locker 의 mysql 구현. GET_LOCK 은 커넥션 단위이므로 락을 잡는 동안 커넥션 하나를 점유함
+// GET_LOCK 에는 만료가 없으므로 ttl 은 무시되고, Renew 는 커넥션이 살아있고 여전히 락을 소유하는지만 확인함
+type Locker struct {
+	handler *Handler
+}
+
+type lease struct {
+	conn *sql.Conn
+	name string
+}
+
+func NewLocker(handler *Handler) *Locker {
+	result := new(Locker)
+	result.handler = handler
+
+	return result
+}
+
+// LockName GET_LOCK 에 넘길 이름. mysql 락 이름은 64자 제한이 있으므로 넘어가면 해시로 바꿈
+func LockName(key string) string {
+	if len(key) <= 64 {
+		return key
+	}
+
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (l *Locker) Acquire(ctx context.Context, key string, ttl time.Duration) (scheduler.Lease, error) {
+	conn, err := l.handler.sqlHandler.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := LockName(key)
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", name).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if !acquired.Valid {
+		_ = conn.Close()
+		return nil, errors.New("xmysql: GET_LOCK failed")
+	}
+	if acquired.Int64 != 1 {
+		_ = conn.Close()
+		return nil, scheduler.ErrLockNotAcquired
+	}
+
+	return &lease{conn: conn, name: name}, nil
+}
+
+func (l *lease) Renew(ctx context.Context, ttl time.Duration) error {
+	var owned sql.NullBool
+	if err := l.conn.QueryRowContext(ctx, "SELECT IS_USED_LOCK(?) = CONNECTION_ID()", l.name).Scan(&owned); err != nil {
+		return err
+	}
+	if !owned.Valid || !owned.Bool {
+		return scheduler.ErrLockLost
+	}
+
+	return nil
+}
+
+func (l *lease) Release(ctx context.Context) error {
+	defer l.conn.Close()
+
+	_, err := l.conn.ExecContext(ctx, "DO RELEASE_LOCK(?)", l.name)
+	return err
+}
@@ -0,0 +1,483 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/newbiediver/golib/xmysql"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+## 사용법 ##
+파일 이름은 <버전>_<이름>.up.sql / <버전>_<이름>.down.sql (예: 0001_create_user.up.sql)
+한 파일에 여러 문장을 넣을 수 있음 (NewHandlerWithConfig 로 만든 핸들러는 multiStatements 가 켜져 있음)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+func migrateDB(handler *xmysql.Handler) error {
+	sub, _ := fs.Sub(migrations, "migrations")
+	migrator := migrate.New(handler, sub)
+
+	steps, err := migrator.Up(context.Background())
+	for _, step := range steps {
+		fmt.Printf("%s %d %s\n", step.Direction, step.Version, step.Name)
+	}
+	return err
+}
+
+// 특정 버전으로 (내려가는 것도 가능)
+migrator.Migrate(ctx, 3)
+
+// 실제로 실행하지 않고 계획만 확인
+steps, err := migrator.SetDryRun(true).Up(ctx)
+*/
+
+type Direction string
+
+const (
+	DirectionUp   Direction = "up"
+	DirectionDown Direction = "down"
+)
+
+const (
+	defaultTable       = "schema_migrations"
+	defaultLockTimeout = time.Minute
+	errNoSuchTable     = 1146
+)
+
+var (
+	ErrLockTimeout = errors.New("migrate: timed out waiting for migration lock")
+	ErrNoDown      = errors.New("migrate: migration has no down file")
+	ErrBadSteps    = errors.New("migrate: down steps must be positive")
+)
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration 한 버전의 up/down SQL. Checksum 은 up 파일 내용의 sha256
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Step 실행했거나 (dry-run 이면) 실행할 예정인 한 단계
+type Step struct {
+	Version   int64
+	Name      string
+	Direction Direction
+	SQL       string
+}
+
+// Status 버전별 적용 상태
+type Status struct {
+	Version   int64     `json:"version"`
+	Name      string    `json:"name"`
+	Applied   bool      `json:"applied"`
+	AppliedAt time.Time `json:"appliedAt,omitempty"`
+	Drift     bool      `json:"drift"`
+}
+
+// DriftError 이미 적용된 파일의 내용이 바뀌었거나 파일이 없어졌을 때
+type DriftError struct {
+	Version int64
+	Name    string
+	Missing bool
+}
+
+func (e *DriftError) Error() string {
+	if e.Missing {
+		return fmt.Sprintf("migrate: applied migration %d (%s) is missing from source", e.Version, e.Name)
+	}
+	return fmt.Sprintf("migrate: checksum of applied migration %d (%s) has changed", e.Version, e.Name)
+}
+
+type appliedRecord struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+type Migrator struct {
+	handler     *xmysql.Handler
+	source      fs.FS
+	table       string
+	lockTimeout time.Duration
+	dryRun      bool
+}
+
+// New fsys 루트에 있는 마이그레이션 파일을 사용. embed.FS 의 하위 디렉토리는 fs.Sub 로 넘기면 됨
+func New(handler *xmysql.Handler, fsys fs.FS) *Migrator {
+	result := new(Migrator)
+	result.handler = handler
+	result.source = fsys
+	result.table = defaultTable
+	result.lockTimeout = defaultLockTimeout
+
+	return result
+}
+
+// NewFromDir 디렉토리에 있는 마이그레이션 파일을 사용
+func NewFromDir(handler *xmysql.Handler, dir string) *Migrator {
+	return New(handler, os.DirFS(dir))
+}
+
+// SetTable 적용 기록 테이블 이름 (기본 schema_migrations)
+func (m *Migrator) SetTable(table string) *Migrator {
+	m.table = table
+	return m
+}
+
+// SetLockTimeout 다른 레플리카가 마이그레이션 중일 때 락을 기다리는 최대 시간
+func (m *Migrator) SetLockTimeout(timeout time.Duration) *Migrator {
+	m.lockTimeout = timeout
+	return m
+}
+
+// SetDryRun true 면 락과 기록 조회만 하고 SQL 은 실행하지 않음
+func (m *Migrator) SetDryRun(dryRun bool) *Migrator {
+	m.dryRun = dryRun
+	return m
+}
+
+// Load 소스의 마이그레이션을 버전 순으로 읽음
+func (m *Migrator) Load() ([]*Migration, error) {
+	entries, err := fs.ReadDir(m.source, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: invalid version in %s: %w", entry.Name(), err)
+		}
+
+		body, err := fs.ReadFile(m.source, path.Clean(entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migrate: version %d has conflicting names %q and %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == string(DirectionUp) {
+			if migration.Up != "" {
+				return nil, fmt.Errorf("migrate: duplicate up file for version %d", version)
+			}
+			sum := sha256.Sum256(body)
+			migration.Up = string(body)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	result := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Checksum == "" {
+			return nil, fmt.Errorf("migrate: version %d has no up file", migration.Version)
+		}
+		result = append(result, migration)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+
+	return result, nil
+}
+
+// Up 모든 미적용 마이그레이션을 적용
+func (m *Migrator) Up(ctx context.Context) ([]Step, error) {
+	migrations, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+	if len(migrations) == 0 {
+		return nil, nil
+	}
+
+	return m.migrate(ctx, migrations, migrations[len(migrations)-1].Version)
+}
+
+// Down 가장 최근에 적용된 마이그레이션부터 steps 개를 되돌림. steps 가 0 이하이면 ErrBadSteps
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Step, error) {
+	if steps <= 0 {
+		return nil, ErrBadSteps
+	}
+
+	migrations, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) ([]Step, error) {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return nil, err
+		}
+
+		versions := sortedVersions(applied)
+		target := int64(0)
+		if steps < len(versions) {
+			target = versions[len(versions)-1-steps]
+		}
+
+		return m.run(ctx, conn, migrations, applied, target)
+	})
+}
+
+// Migrate target 버전까지 올리거나 내림. target 보다 낮은 미적용 버전도 함께 적용됨
+func (m *Migrator) Migrate(ctx context.Context, target int64) ([]Step, error) {
+	migrations, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return m.migrate(ctx, migrations, target)
+}
+
+// Status 소스와 적용 기록을 합친 버전별 상태
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	migrations, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := m.handler.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, 0, len(migrations))
+	for _, migration := range migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.appliedAt
+			status.Drift = record.checksum != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		result = append(result, status)
+	}
+
+	for version, record := range applied {
+		result = append(result, Status{Version: version, Name: record.name, Applied: true, AppliedAt: record.appliedAt, Drift: true})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+
+	return result, nil
+}
+
+func (m *Migrator) migrate(ctx context.Context, migrations []*Migration, target int64) ([]Step, error) {
+	return m.withLock(ctx, func(conn *sql.Conn) ([]Step, error) {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return nil, err
+		}
+
+		return m.run(ctx, conn, migrations, applied, target)
+	})
+}
+
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, migrations []*Migration, applied map[int64]appliedRecord, target int64) ([]Step, error) {
+	if err := checkDrift(migrations, applied); err != nil {
+		return nil, err
+	}
+
+	var plan []Step
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= target {
+			plan = append(plan, Step{Version: migration.Version, Name: migration.Name, Direction: DirectionUp, SQL: migration.Up})
+		}
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; ok && migration.Version > target {
+			if migration.Down == "" {
+				return nil, fmt.Errorf("%w: %d (%s)", ErrNoDown, migration.Version, migration.Name)
+			}
+			plan = append(plan, Step{Version: migration.Version, Name: migration.Name, Direction: DirectionDown, SQL: migration.Down})
+		}
+	}
+
+	if m.dryRun {
+		return plan, nil
+	}
+
+	checksums := make(map[int64]string, len(migrations))
+	for _, migration := range migrations {
+		checksums[migration.Version] = migration.Checksum
+	}
+
+	var done []Step
+	for _, step := range plan {
+		if err := m.apply(ctx, conn, step, checksums[step.Version]); err != nil {
+			return done, fmt.Errorf("migrate: %s %d (%s): %w", step.Direction, step.Version, step.Name, err)
+		}
+		done = append(done, step)
+	}
+
+	return done, nil
+}
+
+// apply DDL 은 암묵적으로 커밋되므로 SQL 과 기록을 하나의 트랜잭션으로 묶을 수 없음. SQL 이 성공한 뒤에 기록함
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, step Step, checksum string) error {
+	if strings.TrimSpace(step.SQL) != "" {
+		if _, err := conn.ExecContext(ctx, step.SQL); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if step.Direction == DirectionUp {
+		_, err = conn.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)", m.quotedTable()),
+			step.Version, step.Name, checksum, time.Now().UTC())
+	} else {
+		_, err = conn.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE version = ?", m.quotedTable()), step.Version)
+	}
+
+	return err
+}
+
+func checkDrift(migrations []*Migration, applied map[int64]appliedRecord) error {
+	byVersion := make(map[int64]*Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	for _, version := range sortedVersions(applied) {
+		record := applied[version]
+		migration, ok := byVersion[version]
+		if !ok {
+			return &DriftError{Version: version, Name: record.name, Missing: true}
+		}
+		if migration.Checksum != record.checksum {
+			return &DriftError{Version: version, Name: migration.Name}
+		}
+	}
+
+	return nil
+}
+
+// withLock 커넥션 하나를 점유해서 GET_LOCK 을 잡고 fn 을 실행함. 같은 테이블을 쓰는 다른 레플리카는 락이 풀릴 때까지 기다림
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) ([]Step, error)) ([]Step, error) {
+	conn, err := m.handler.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	name := xmysql.LockName("xmysql_migrate:" + m.table)
+	seconds := int64(m.lockTimeout / time.Second)
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", name, seconds).Scan(&acquired); err != nil {
+		return nil, err
+	}
+	if !acquired.Valid {
+		return nil, errors.New("migrate: GET_LOCK failed")
+	}
+	if acquired.Int64 != 1 {
+		return nil, ErrLockTimeout
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "DO RELEASE_LOCK(?)", name)
+	}()
+
+	if !m.dryRun {
+		if err := m.ensureTable(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	version BIGINT NOT NULL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	checksum CHAR(64) NOT NULL,
+	applied_at DATETIME(6) NOT NULL
+)`, m.quotedTable()))
+	return err
+}
+
+// applied 적용 기록. 테이블이 아직 없으면 (dry-run 이나 Status) 빈 기록으로 취급
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]appliedRecord, error) {
+	result := make(map[int64]appliedRecord)
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT version, name, checksum, applied_at FROM %s", m.quotedTable()))
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == errNoSuchTable {
+			return result, nil
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			version int64
+			record  appliedRecord
+		)
+		if err := rows.Scan(&version, &record.name, &record.checksum, &record.appliedAt); err != nil {
+			return nil, err
+		}
+		result[version] = record
+	}
+
+	return result, rows.Err()
+}
+
+func (m *Migrator) quotedTable() string {
+	return "`" + strings.ReplaceAll(m.table, "`", "``") + "`"
+}
+
+func sortedVersions(applied map[int64]appliedRecord) []int64 {
+	result := make([]int64, 0, len(applied))
+	for version := range applied {
+		result = append(result, version)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+
+	return result
+}
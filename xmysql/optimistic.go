@@ -0,0 +1,149 @@
+package xmysql
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+/*
+## 사용법 ##
+err := handler.UpdateOptimistic(ctx, nil, func(ctx context.Context, attempt int) (xmysql.StatementBuilder, error) {
+	var gold, version int64
+	rs, err := handler.SyncQueryContext(ctx, "SELECT gold, version FROM wallet WHERE uid = ?", uid)
+	...		// 매 시도마다 최신 값을 다시 읽음
+
+	if gold < price {
+		return nil, ErrNotEnoughGold		// 에러를 리턴하면 재시도하지 않고 그대로 리턴
+	}
+
+	return xmysql.Update("wallet").
+		Set("gold", gold-price).
+		Where(xmysql.Eq("uid", uid)).
+		Versioned("version", version), nil		// version = version + 1 WHERE ... AND version = ?
+})
+
+// Querier 로 받는 코드는 함수 버전을 사용 (테스트에서 xmysqltest.Fake 로 바꿔 끼울 수 있음)
+err = xmysql.UpdateOptimistic(ctx, querier, nil, fn)
+
+var conflict *xmysql.ConflictError
+if errors.As(err, &conflict) {
+	// MaxAttempts 동안 계속 다른 서버가 먼저 갱신함
+}
+*/
+
+// StatementBuilder SQL 과 파라미터를 만드는 빌더. SelectBuilder, UpdateBuilder 등과 Statement 가 구현
+type StatementBuilder interface {
+	Build() (string, []any, error)
+}
+
+// OptimisticOptions Backoff 는 시도마다 두 배씩 MaxBackoff 까지 늘어나고, 서버끼리 겹치지 않게 0.5~1.5 배의 지터가 붙음
+type OptimisticOptions struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+var defaultOptimisticOptions = OptimisticOptions{
+	MaxAttempts: 5,
+	Backoff:     10 * time.Millisecond,
+	MaxBackoff:  500 * time.Millisecond,
+}
+
+// ConflictError 모든 시도에서 다른 쪽이 먼저 갱신해서 영향받은 행이 없었음
+type ConflictError struct {
+	Attempts int
+	SQL      string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("xmysql: optimistic update conflicted %d times: %s", e.Attempts, e.SQL)
+}
+
+// Build Statement 를 그대로 StatementBuilder 로 쓸 수 있게 함
+func (st Statement) Build() (string, []any, error) {
+	return st.SQL, st.Args, nil
+}
+
+// Versioned 낙관적 락. column 이 current 일 때만 갱신하고 column 을 1 올림
+// 버전이 항상 바뀌므로 다른 값이 그대로여도 영향받은 행 수가 0 이 되지 않음
+func (b *UpdateBuilder) Versioned(column string, current any) *UpdateBuilder {
+	b.sets = append(b.sets, assignment{column: column, expr: quoteIdentifier(column) + " + 1"})
+	b.conds = append(b.conds, Eq(column, current))
+	return b
+}
+
+// UpdateOptimistic 패키지 함수 UpdateOptimistic 을 이 핸들러로 실행
+func (s *Handler) UpdateOptimistic(ctx context.Context, opts *OptimisticOptions, fn func(ctx context.Context, attempt int) (StatementBuilder, error)) error {
+	return UpdateOptimistic(ctx, s, opts, fn)
+}
+
+// UpdateOptimistic fn 이 최신 값을 읽고 버전 조건이 붙은 UPDATE 를 돌려주면 handler 로 실행함
+// 영향받은 행이 0 이면 (다른 쪽이 먼저 갱신) 대기 후 fn 부터 다시 시도하고, MaxAttempts 를 다 쓰면 *ConflictError
+// fn 이 에러를 리턴하면 그대로 리턴하고, nil 빌더를 리턴하면 할 일이 없는 것으로 보고 nil 을 리턴. opts 가 nil 이면 기본값
+func UpdateOptimistic(ctx context.Context, handler Querier, opts *OptimisticOptions, fn func(ctx context.Context, attempt int) (StatementBuilder, error)) error {
+	if opts == nil {
+		opts = &defaultOptimisticOptions
+	}
+
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var sqlString string
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(opts.backoff(attempt)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		builder, err := fn(ctx, attempt)
+		if err != nil {
+			return err
+		}
+		if builder == nil {
+			return nil
+		}
+
+		var args []any
+		if sqlString, args, err = builder.Build(); err != nil {
+			return err
+		}
+
+		result, err := handler.SyncExecuteContext(ctx, sqlString, args...)
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected > 0 {
+			return nil
+		}
+	}
+
+	return &ConflictError{Attempts: maxAttempts, SQL: sqlString}
+}
+
+func (o *OptimisticOptions) backoff(attempt int) time.Duration {
+	if o.Backoff <= 0 {
+		return 0
+	}
+
+	result := o.Backoff
+	for i := 1; i < attempt && (o.MaxBackoff <= 0 || result < o.MaxBackoff); i++ {
+		result *= 2
+	}
+	if o.MaxBackoff > 0 && result > o.MaxBackoff {
+		result = o.MaxBackoff
+	}
+
+	return result/2 + rand.N(result)
+}
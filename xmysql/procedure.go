@@ -0,0 +1,196 @@
+package xmysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+/*
+## 사용법 ##
+// CREATE PROCEDURE sp_get_user(IN p_uid BIGINT, OUT p_level INT, INOUT p_gold BIGINT) ...
+result, err := handler.CallProcedure(ctx, "sp_get_user", uid, xmysql.Out(), xmysql.InOut(100))
+if err != nil {
+	return err
+}
+defer result.Close()
+
+for rs := range result.ResultSets() {
+	for rs.NextRow() {
+		...
+	}
+}
+
+var (
+	level int
+	gold  int64
+)
+if err := result.ScanOut(&level, &gold); err != nil {		// OUT, INOUT 순서대로
+	return err
+}
+*/
+
+type ParamMode int
+
+const (
+	ParamIn ParamMode = 0 + iota
+	ParamOut
+	ParamInOut
+)
+
+// ProcParam CallProcedure 의 OUT, INOUT 파라미터. 그냥 값을 넘기면 IN 파라미터
+type ProcParam struct {
+	Mode  ParamMode
+	Value any
+}
+
+func Out() ProcParam {
+	return ProcParam{Mode: ParamOut}
+}
+
+func InOut(value any) ProcParam {
+	return ProcParam{Mode: ParamInOut, Value: value}
+}
+
+// ProcedureResult 프로시저가 돌려준 결과셋과 OUT 값. OUT 값은 결과셋을 모두 읽은 뒤에 받을 수 있음
+// 사용이 끝나면 반드시 Close 해야 커넥션이 풀로 돌아감
+type ProcedureResult struct {
+	ctx     context.Context
+	conn    *sql.Conn
+	rows    *sql.Rows
+	cancel  context.CancelFunc
+	outVars []string
+	err     error
+	closed  bool
+}
+
+// CallProcedure OUT, INOUT 파라미터는 커넥션 세션 변수로 주고받기 때문에 커넥션 하나를 점유함
+// 프로시저 안에서 트랜잭션을 직접 처리해도 됨 (Transaction 과 달리 트랜잭션 밖에서 호출됨)
+func (s *Handler) CallProcedure(ctx context.Context, name string, params ...any) (*ProcedureResult, error) {
+	if s.isClosed() {
+		return nil, ErrHandlerClosed
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+
+	conn, err := s.sqlHandler.Conn(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	result := &ProcedureResult{ctx: ctx, conn: conn, cancel: cancel}
+
+	var (
+		placeholders []string
+		inArgs       []any
+		sets         []string
+		setArgs      []any
+	)
+	for i, param := range params {
+		p, ok := param.(ProcParam)
+		if !ok || p.Mode == ParamIn {
+			if ok {
+				param = p.Value
+			}
+			placeholders = append(placeholders, "?")
+			inArgs = append(inArgs, param)
+			continue
+		}
+
+		// 풀의 커넥션은 재사용되므로 이전 호출의 값이 남지 않게 OUT 변수도 NULL 로 초기화
+		variable := fmt.Sprintf("@xmysql_p%d", i)
+		placeholders = append(placeholders, variable)
+		result.outVars = append(result.outVars, variable)
+		if p.Mode == ParamInOut {
+			sets = append(sets, variable+" = ?")
+			setArgs = append(setArgs, p.Value)
+		} else {
+			sets = append(sets, variable+" = NULL")
+		}
+	}
+
+	if len(sets) > 0 {
+		if _, err := conn.ExecContext(ctx, "SET "+strings.Join(sets, ", "), setArgs...); err != nil {
+			result.Close()
+			return nil, err
+		}
+	}
+
+	callString := "CALL " + quoteIdentifier(name) + "(" + strings.Join(placeholders, ", ") + ")"
+
+	hookCtx, finish := s.startQuery(ctx, QueryKindQuery, false, callString, inArgs)
+	result.rows, err = conn.QueryContext(hookCtx, callString, inArgs...)
+	finish(nil, err)
+
+	if err != nil {
+		result.Close()
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ResultSets 결과셋을 순서대로 돌려줌. 받은 RecordSet 은 Close 하지 말 것
+// 중간에 멈추면 남은 결과셋은 ScanOut 이나 Close 에서 버려짐
+func (p *ProcedureResult) ResultSets() iter.Seq[*RecordSet] {
+	return func(yield func(*RecordSet) bool) {
+		if p.rows == nil || p.closed {
+			return
+		}
+
+		for {
+			if columns, err := p.rows.Columns(); err == nil && len(columns) > 0 {
+				if !yield(&RecordSet{curRows: p.rows}) {
+					return
+				}
+			}
+
+			if !p.rows.NextResultSet() {
+				break
+			}
+		}
+
+		p.err = p.rows.Err()
+	}
+}
+
+// Err 결과셋을 읽는 중에 난 에러
+func (p *ProcedureResult) Err() error {
+	return p.err
+}
+
+// ScanOut OUT, INOUT 파라미터 값을 파라미터 순서대로 읽음. 남은 결과셋은 버려짐
+func (p *ProcedureResult) ScanOut(dest ...any) error {
+	if p.closed {
+		return errors.New("xmysql: procedure result is closed")
+	}
+	if len(dest) != len(p.outVars) {
+		return fmt.Errorf("xmysql: procedure has %d out parameters, got %d destinations", len(p.outVars), len(dest))
+	}
+	if len(dest) == 0 {
+		return nil
+	}
+
+	if err := p.rows.Close(); err != nil {
+		return err
+	}
+
+	return p.conn.QueryRowContext(p.ctx, "SELECT "+strings.Join(p.outVars, ", ")).Scan(dest...)
+}
+
+func (p *ProcedureResult) Close() {
+	if p.closed {
+		return
+	}
+	p.closed = true
+
+	if p.rows != nil {
+		_ = p.rows.Close()
+	}
+	_ = p.conn.Close()
+	p.cancel()
+}
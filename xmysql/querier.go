@@ -0,0 +1,33 @@
+package xmysql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Querier Handler 의 쿼리 API. 이 인터페이스로 받으면 테스트에서 xmysqltest.Fake 로 바꿔 끼울 수 있음
+// QueryRows, Stream, BulkLoad, UpdateOptimistic 도 Querier 를 받음
+type Querier interface {
+	Query(executor *QueryExecutor)
+	QueryContext(ctx context.Context, executor *QueryExecutor)
+	SyncQuery(sqlString string, args ...any) (*RecordSet, error)
+	SyncQueryContext(ctx context.Context, sqlString string, args ...any) (*RecordSet, error)
+	Execute(queryString string, execCallback ExecCallback, errCallback ErrorCallback, args ...any)
+	ExecuteContext(ctx context.Context, queryString string, execCallback ExecCallback, errCallback ErrorCallback, args ...any)
+	Transaction(onCommit CommitCallback, onRollback RollbackCallback, queries ...*QueryExecutor)
+	TransactionContext(ctx context.Context, onCommit CommitCallback, onRollback RollbackCallback, queries ...*QueryExecutor)
+	SyncExecute(sqlString string, args ...any) (sql.Result, error)
+	SyncExecuteContext(ctx context.Context, sqlString string, args ...any) (sql.Result, error)
+	ExecuteBatch(ctx context.Context, statements ...Statement) error
+	MaxAllowedPacket(ctx context.Context) (int, error)
+}
+
+var (
+	_ Querier = (*Handler)(nil)
+	_ Querier = (*ReplicaSet)(nil)
+)
+
+// NewRecordSet 다른 곳에서 얻은 *sql.Rows 를 RecordSet 으로 감쌈 (테스트 대역 등)
+func NewRecordSet(rows *sql.Rows) *RecordSet {
+	return &RecordSet{curRows: rows}
+}
@@ -0,0 +1,190 @@
+package xmysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+)
+
+/*
+## 사용법 ##
+replicaSet := xmysql.NewReplicaSet("game", primary, []*xmysql.Handler{replica1, replica2}, xmysql.ReplicaRoundRobin)
+
+replicaSet.Query(&selectQuery)								// 레플리카
+replicaSet.Execute("UPDATE ...", onExec, onError)				// 프라이머리
+
+// 방금 쓴 데이터를 읽어야 할 때는 프라이머리로 강제
+replicaSet.QueryContext(xmysql.WithPrimary(ctx), &selectQuery)
+*/
+
+// ReplicaPolicy 읽기 쿼리를 보낼 레플리카를 고르는 방식
+type ReplicaPolicy int
+
+const (
+	ReplicaRoundRobin       ReplicaPolicy = 0 + iota
+	ReplicaLeastConnections               // 사용중인 커넥션이 가장 적은 레플리카
+)
+
+type primaryKey struct{}
+
+// ReplicaSet 쓰기와 트랜잭션은 Primary 로, 읽기는 healthy 한 레플리카로 보냄
+// 프라이머리와 레플리카는 헬스체크 대상으로 등록되며, 실패한 레플리카는 다시 healthy 가 될 때까지 제외됨. 모두 실패하면 Primary 로 읽음
+type ReplicaSet struct {
+	Primary  *Handler
+	Replicas []*Handler
+	policy   ReplicaPolicy
+	next     atomic.Uint64
+}
+
+// NewReplicaSet name 은 헬스체크 이름의 접두사로 "<name>-primary", "<name>-replica-<i>" 로 등록됨
+// 레플리카셋이나 샤드가 여러개면 서로 다른 이름을 써야 함. 이미 KeepHandler 등으로 등록된 핸들러는 기존 이름을 유지함
+func NewReplicaSet(name string, primary *Handler, replicas []*Handler, policy ReplicaPolicy) *ReplicaSet {
+	result := new(ReplicaSet)
+	result.Primary = primary
+	result.Replicas = replicas
+	result.policy = policy
+
+	monitorHandlerOnce(name+"-primary", primary)
+	for i, replica := range replicas {
+		monitorHandlerOnce(fmt.Sprintf("%s-replica-%d", name, i), replica)
+	}
+
+	return result
+}
+
+// WithPrimary 이 ctx 로 호출한 읽기 쿼리는 프라이머리로 감 (read-after-write)
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v
+}
+
+// Reader 읽기 쿼리를 보낼 핸들러
+func (r *ReplicaSet) Reader(ctx context.Context) *Handler {
+	if usePrimary(ctx) {
+		return r.Primary
+	}
+
+	if replica := r.pickReplica(); replica != nil {
+		return replica
+	}
+
+	return r.Primary
+}
+
+func (r *ReplicaSet) pickReplica() *Handler {
+	count := len(r.Replicas)
+	if count == 0 {
+		return nil
+	}
+
+	if r.policy == ReplicaLeastConnections {
+		var (
+			result *Handler
+			inUse  int
+		)
+		for _, replica := range r.Replicas {
+			if !replica.Healthy() {
+				continue
+			}
+			if n := replica.Stats().InUse; result == nil || n < inUse {
+				result = replica
+				inUse = n
+			}
+		}
+		return result
+	}
+
+	start := r.next.Add(1)
+	for i := 0; i < count; i++ {
+		replica := r.Replicas[(start+uint64(i))%uint64(count)]
+		if replica.Healthy() {
+			return replica
+		}
+	}
+
+	return nil
+}
+
+func (r *ReplicaSet) Query(executor *QueryExecutor) {
+	r.QueryContext(context.Background(), executor)
+}
+
+func (r *ReplicaSet) QueryContext(ctx context.Context, executor *QueryExecutor) {
+	r.Reader(ctx).QueryContext(ctx, executor)
+}
+
+func (r *ReplicaSet) SyncQuery(sqlString string, args ...any) (*RecordSet, error) {
+	return r.SyncQueryContext(context.Background(), sqlString, args...)
+}
+
+func (r *ReplicaSet) SyncQueryContext(ctx context.Context, sqlString string, args ...any) (*RecordSet, error) {
+	return r.Reader(ctx).SyncQueryContext(ctx, sqlString, args...)
+}
+
+func (r *ReplicaSet) Execute(queryString string, execCallback ExecCallback, errCallback ErrorCallback, args ...any) {
+	r.Primary.Execute(queryString, execCallback, errCallback, args...)
+}
+
+func (r *ReplicaSet) ExecuteContext(ctx context.Context, queryString string, execCallback ExecCallback, errCallback ErrorCallback, args ...any) {
+	r.Primary.ExecuteContext(ctx, queryString, execCallback, errCallback, args...)
+}
+
+func (r *ReplicaSet) SyncExecute(sqlString string, args ...any) (sql.Result, error) {
+	return r.Primary.SyncExecute(sqlString, args...)
+}
+
+func (r *ReplicaSet) SyncExecuteContext(ctx context.Context, sqlString string, args ...any) (sql.Result, error) {
+	return r.Primary.SyncExecuteContext(ctx, sqlString, args...)
+}
+
+func (r *ReplicaSet) ExecuteBatch(ctx context.Context, statements ...Statement) error {
+	return r.Primary.ExecuteBatch(ctx, statements...)
+}
+
+func (r *ReplicaSet) MaxAllowedPacket(ctx context.Context) (int, error) {
+	return r.Primary.MaxAllowedPacket(ctx)
+}
+
+func (r *ReplicaSet) Transaction(onCommit CommitCallback, onRollback RollbackCallback, queries ...*QueryExecutor) {
+	r.Primary.Transaction(onCommit, onRollback, queries...)
+}
+
+func (r *ReplicaSet) TransactionContext(ctx context.Context, onCommit CommitCallback, onRollback RollbackCallback, queries ...*QueryExecutor) {
+	r.Primary.TransactionContext(ctx, onCommit, onRollback, queries...)
+}
+
+func (r *ReplicaSet) WithTx(ctx context.Context, fn func(tx *Tx) error) error {
+	return r.Primary.WithTx(ctx, fn)
+}
+
+// UpdateOptimistic 프라이머리에서 실행. fn 안의 읽기도 WithPrimary 나 Primary 로 해야 최신 버전을 읽음
+func (r *ReplicaSet) UpdateOptimistic(ctx context.Context, opts *OptimisticOptions, fn func(ctx context.Context, attempt int) (StatementBuilder, error)) error {
+	return r.Primary.UpdateOptimistic(ctx, opts, fn)
+}
+
+// AddQueryHook 프라이머리와 모든 레플리카에 훅을 추가
+func (r *ReplicaSet) AddQueryHook(hook QueryHook) {
+	r.Primary.AddQueryHook(hook)
+	for _, replica := range r.Replicas {
+		replica.AddQueryHook(hook)
+	}
+}
+
+// Close 프라이머리와 모든 레플리카를 닫고 헬스체크 대상에서 뺌
+func (r *ReplicaSet) Close(ctx context.Context) error {
+	UnmonitorHandler(r.Primary)
+	result := r.Primary.Close(ctx)
+	for _, replica := range r.Replicas {
+		UnmonitorHandler(replica)
+		if err := replica.Close(ctx); err != nil && result == nil {
+			result = err
+		}
+	}
+
+	return result
+}
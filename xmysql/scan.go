@@ -0,0 +1,254 @@
+package xmysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+/*
+## 사용법 ##
+type User struct {
+	ID        int64          `db:"id"`
+	Name      string         `db:"name"`
+	Nickname  *string        `db:"nickname"`		// NULL 이면 nil
+	Email     sql.NullString `db:"email"`
+	CreatedAt time.Time										// 태그가 없으면 필드 이름(created_at, createdat) 으로 매칭
+	Audit													// 임베디드 구조체의 필드도 매칭됨
+	Memo      string         `db:"-"`				// 무시
+}
+
+users, err := xmysql.QueryRows[User](ctx, handler, "SELECT * FROM user WHERE level > ?", 10)
+ids, err := xmysql.QueryRows[int64](ctx, handler, "SELECT id FROM user")		// 구조체가 아니면 첫 컬럼을 바로 스캔
+*/
+
+type structField struct {
+	index []int
+}
+
+var (
+	structFieldCache sync.Map
+	scannerType      = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// QueryRows 쿼리 결과를 T 의 슬라이스로 받음
+func QueryRows[T any](ctx context.Context, handler Querier, sqlString string, args ...any) ([]T, error) {
+	rs, err := handler.SyncQueryContext(ctx, sqlString, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rs.Close()
+
+	var result []T
+	if err := rs.ScanAll(&result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ScanStruct NextRow 로 옮긴 현재 행을 dst 구조체에 db 태그 기준으로 채움. 구조체에 없는 컬럼은 버림
+func (rs *RecordSet) ScanStruct(dst any) error {
+	if rs.curRows == nil {
+		return errors.New("no recordset data")
+	}
+
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return errors.New("xmysql: ScanStruct destination must be a non-nil pointer")
+	}
+
+	columns, err := rs.curRows.Columns()
+	if err != nil {
+		return err
+	}
+
+	return rs.scanValue(v.Elem(), columns)
+}
+
+// ScanAll 남은 행을 모두 dst 슬라이스에 채움. dst 는 *[]T 또는 *[]*T
+func (rs *RecordSet) ScanAll(dst any) error {
+	if rs.curRows == nil {
+		return errors.New("no recordset data")
+	}
+
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return errors.New("xmysql: ScanAll destination must be a pointer to a slice")
+	}
+
+	columns, err := rs.curRows.Columns()
+	if err != nil {
+		return err
+	}
+
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+	isPointer := elemType.Kind() == reflect.Pointer
+	if isPointer {
+		elemType = elemType.Elem()
+	}
+
+	for rs.curRows.Next() {
+		elem := reflect.New(elemType)
+		if err := rs.scanValue(elem.Elem(), columns); err != nil {
+			return err
+		}
+
+		if isPointer {
+			slice = reflect.Append(slice, elem)
+		} else {
+			slice = reflect.Append(slice, elem.Elem())
+		}
+	}
+
+	v.Elem().Set(slice)
+	return rs.curRows.Err()
+}
+
+func (rs *RecordSet) scanValue(v reflect.Value, columns []string) error {
+	if !isStructTarget(v.Type()) {
+		if len(columns) != 1 {
+			return fmt.Errorf("xmysql: scanning %s needs exactly one column, got %d", v.Type(), len(columns))
+		}
+		return rs.curRows.Scan(v.Addr().Interface())
+	}
+
+	fields := structFields(v.Type())
+	targets := make([]any, len(columns))
+	for i, column := range columns {
+		field, ok := fields[strings.ToLower(column)]
+		if !ok {
+			targets[i] = new(any)
+			continue
+		}
+
+		targets[i] = fieldByIndex(v, field.index).Addr().Interface()
+	}
+
+	return rs.curRows.Scan(targets...)
+}
+
+// isStructTarget time.Time, sql.NullString 처럼 스스로 Scan 하는 구조체는 컬럼 하나로 취급
+func isStructTarget(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	if reflect.PointerTo(t).Implements(scannerType) {
+		return false
+	}
+
+	return t.PkgPath() != "time" || t.Name() != "Time"
+}
+
+// fieldByIndex 임베디드 포인터 구조체가 nil 이면 만들어가며 따라감
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+func structFields(t reflect.Type) map[string]structField {
+	if cached, ok := structFieldCache.Load(t); ok {
+		return cached.(map[string]structField)
+	}
+
+	result := make(map[string]structField)
+	collectFields(t, nil, result)
+
+	structFieldCache.Store(t, result)
+	return result
+}
+
+// collectFields 바깥 구조체의 필드가 임베디드 구조체의 같은 이름 필드보다 우선함
+func collectFields(t reflect.Type, parent []int, result map[string]structField) {
+	var embedded []reflect.StructField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("db")
+		if tag == "-" {
+			continue
+		}
+
+		index := append(append([]int(nil), parent...), i)
+
+		if f.Anonymous && !hasTag {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				// 비공개 타입의 포인터는 reflect 로 할당할 수 없음
+				if !f.IsExported() {
+					continue
+				}
+				ft = ft.Elem()
+			}
+			if isStructTarget(ft) {
+				f.Index = index
+				embedded = append(embedded, f)
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if hasTag && tag != "" {
+			result[strings.ToLower(tag)] = structField{index: index}
+			continue
+		}
+
+		for _, name := range []string{strings.ToLower(f.Name), toSnakeCase(f.Name)} {
+			if _, ok := result[name]; !ok {
+				result[name] = structField{index: index}
+			}
+		}
+	}
+
+	for _, f := range embedded {
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		nested := make(map[string]structField)
+		collectFields(ft, f.Index, nested)
+		for name, field := range nested {
+			if _, ok := result[name]; !ok {
+				result[name] = field
+			}
+		}
+	}
+}
+
+func toSnakeCase(name string) string {
+	var b strings.Builder
+
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
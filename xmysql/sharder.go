@@ -1,17 +1,54 @@
 package xmysql
 
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+/*
+## 사용법 ##
+sharder := xmysql.NewSharder(4)
+sharder.Main = mainHandler
+sharder.Sharding[0] = ...
+
+// 기본은 hash % 샤드수 (hash 0 은 Main). 샤드를 늘릴 때 키 이동을 줄이려면 consistent hash 사용
+if err := sharder.SetStrategy(xmysql.NewConsistentHashStrategy(len(sharder.Sharding), 160)); err != nil {
+	log.Fatal(err)
+}
+
+handler := sharder.GetHandler(uid)
+
+// 관리자 리포트: 모든 샤드에 같은 쿼리를 병렬로 실행하고 결과를 합침
+rows, err := xmysql.QueryAllShards[Report](ctx, sharder, "SELECT level, COUNT(*) AS cnt FROM user GROUP BY level")
+*/
+
 type Sharder struct {
 	Main     *Handler
 	Sharding []*Handler
+	strategy ShardStrategy
 }
 
 func NewSharder(shardingCapacity int) *Sharder {
 	var result = new(Sharder)
 	result.Sharding = make([]*Handler, shardingCapacity)
+	result.strategy = ModuloStrategy{}
 
 	return result
 }
 
+// SetStrategy 샤드 선택 방식 변경. 전략이 ShardValidator 이면 len(Sharding) 으로 확인하고 실패하면 바꾸지 않음
+func (s *Sharder) SetStrategy(strategy ShardStrategy) error {
+	if validator, ok := strategy.(ShardValidator); ok {
+		if err := validator.Validate(len(s.Sharding)); err != nil {
+			return err
+		}
+	}
+
+	s.strategy = strategy
+	return nil
+}
+
 func (s *Sharder) FlushSharder() {
 	backgroundObject.Stop()
 	_ = s.Main.sqlHandler.Close()
@@ -20,10 +57,110 @@ func (s *Sharder) FlushSharder() {
 	}
 }
 
+// GetHandler 전략이 고른 샤드. 전략이 Main 을 고르거나(-1) 범위를 벗어나면 Main
 func (s *Sharder) GetHandler(hash int64) *Handler {
-	if hash == 0 {
+	strategy := s.strategy
+	if strategy == nil {
+		strategy = ModuloStrategy{}
+	}
+
+	index := strategy.ShardIndex(hash, len(s.Sharding))
+	if index < 0 || index >= len(s.Sharding) {
 		return s.Main
 	}
 
-	return s.Sharding[hash%int64(len(s.Sharding))]
+	return s.Sharding[index]
+}
+
+// GetHandlerByString 문자열 키는 HashString 으로 바꿔서 고름
+func (s *Sharder) GetHandlerByString(key string) *Handler {
+	return s.GetHandler(HashString(key))
+}
+
+// ForEachShard Sharding 의 모든 핸들러에 fn 을 병렬로 실행. 실패한 샤드의 에러는 모아서 리턴
+func (s *Sharder) ForEachShard(ctx context.Context, fn func(ctx context.Context, index int, handler *Handler) error) error {
+	var (
+		wait sync.WaitGroup
+		lock sync.Mutex
+		errs ShardErrors
+	)
+
+	for i, handler := range s.Sharding {
+		wait.Add(1)
+		go func(index int, handler *Handler) {
+			defer wait.Done()
+
+			if err := fn(ctx, index, handler); err != nil {
+				lock.Lock()
+				errs = append(errs, ShardError{Shard: index, Err: err})
+				lock.Unlock()
+			}
+		}(i, handler)
+	}
+
+	wait.Wait()
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// QueryAllShards Sharding 의 모든 샤드에 쿼리를 병렬로 실행하고 결과를 샤드 순서대로 이어붙임 (Main 은 제외)
+func QueryAllShards[T any](ctx context.Context, sharder *Sharder, sqlString string, args ...any) ([]T, error) {
+	results := make([][]T, len(sharder.Sharding))
+
+	err := sharder.ForEachShard(ctx, func(ctx context.Context, index int, handler *Handler) error {
+		rows, err := QueryRows[T](ctx, handler, sqlString, args...)
+		if err != nil {
+			return err
+		}
+
+		results[index] = rows
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var merged []T
+	for _, rows := range results {
+		merged = append(merged, rows...)
+	}
+
+	return merged, nil
+}
+
+type ShardError struct {
+	Shard int
+	Err   error
+}
+
+func (e ShardError) Error() string {
+	return fmt.Sprintf("shard %d: %s", e.Shard, e.Err)
+}
+
+func (e ShardError) Unwrap() error {
+	return e.Err
+}
+
+// ShardErrors ForEachShard 에서 실패한 샤드들의 에러
+type ShardErrors []ShardError
+
+func (e ShardErrors) Error() string {
+	result := fmt.Sprintf("%d shard(s) failed", len(e))
+	for _, err := range e {
+		result += "; " + err.Error()
+	}
+
+	return result
+}
+
+func (e ShardErrors) Unwrap() []error {
+	result := make([]error, 0, len(e))
+	for _, err := range e {
+		result = append(result, err)
+	}
+
+	return result
 }
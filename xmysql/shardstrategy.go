@@ -0,0 +1,234 @@
+package xmysql
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"hash/fnv"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// ShardStrategy 키가 속할 샤드 인덱스를 고름. -1 이면 Sharder.Main
+type ShardStrategy interface {
+	ShardIndex(key int64, shardCount int) int
+}
+
+// ShardValidator 전략이 고를 수 있는 샤드 인덱스가 샤드 수 안에 있는지 확인함. Sharder.SetStrategy 에서 호출
+type ShardValidator interface {
+	Validate(shardCount int) error
+}
+
+// ModuloStrategy 기존 방식: key % shardCount, key 0 은 Main
+type ModuloStrategy struct{}
+
+func (ModuloStrategy) ShardIndex(key int64, shardCount int) int {
+	if key == 0 || shardCount <= 0 {
+		return -1
+	}
+
+	index := key % int64(shardCount)
+	if index < 0 {
+		index += int64(shardCount)
+	}
+
+	return int(index)
+}
+
+// HashString 문자열 키를 샤드 선택용 정수로 바꿈 (FNV-1a)
+func HashString(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+
+	return int64(h.Sum64() >> 1)
+}
+
+func hashInt64(key int64) uint64 {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(key))
+
+	h := fnv.New64a()
+	_, _ = h.Write(b[:])
+	return mix64(h.Sum64())
+}
+
+// mix64 FNV 만으로는 비슷한 입력이 링에 고르게 퍼지지 않으므로 한번 더 섞음 (splitmix64 finalizer)
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+type ringNode struct {
+	hash  uint64
+	shard int
+}
+
+// ConsistentHashStrategy 가상 노드를 가진 consistent hash 링. 샤드를 하나 늘리면 약 1/N 의 키만 옮겨짐
+// 링은 만들 때의 샤드 수로 고정되므로 shardCount 인자는 무시됨
+type ConsistentHashStrategy struct {
+	ring       []ringNode
+	shardCount int
+}
+
+func NewConsistentHashStrategy(shardCount, virtualNodes int) *ConsistentHashStrategy {
+	if virtualNodes <= 0 {
+		virtualNodes = 160
+	}
+
+	result := new(ConsistentHashStrategy)
+	result.shardCount = shardCount
+	result.ring = make([]ringNode, 0, shardCount*virtualNodes)
+	for shard := 0; shard < shardCount; shard++ {
+		for v := 0; v < virtualNodes; v++ {
+			h := fnv.New64a()
+			_, _ = h.Write([]byte("shard-" + strconv.Itoa(shard) + "-" + strconv.Itoa(v)))
+			result.ring = append(result.ring, ringNode{hash: mix64(h.Sum64()), shard: shard})
+		}
+	}
+
+	sort.Slice(result.ring, func(i, j int) bool {
+		return result.ring[i].hash < result.ring[j].hash
+	})
+
+	return result
+}
+
+func (c *ConsistentHashStrategy) ShardIndex(key int64, _ int) int {
+	if len(c.ring) == 0 {
+		return -1
+	}
+
+	h := hashInt64(key)
+	i := sort.Search(len(c.ring), func(i int) bool {
+		return c.ring[i].hash >= h
+	})
+	if i == len(c.ring) {
+		i = 0
+	}
+
+	return c.ring[i].shard
+}
+
+func (c *ConsistentHashStrategy) Validate(shardCount int) error {
+	if c.shardCount > shardCount {
+		return fmt.Errorf("xmysql: consistent hash ring has %d shards but sharder has %d", c.shardCount, shardCount)
+	}
+
+	return nil
+}
+
+// ShardRange From 이상 To 미만의 키는 Shard 로
+type ShardRange struct {
+	From  int64 `yaml:"from"`
+	To    int64 `yaml:"to"`
+	Shard int   `yaml:"shard"`
+}
+
+// RangeStrategy Lookup 에 있는 키를 먼저 보고, 그 다음 Ranges 를 봄. 둘 다 없으면 Fallback (nil 이면 Main)
+type RangeStrategy struct {
+	Ranges   []ShardRange  `yaml:"ranges"`
+	Lookup   map[int64]int `yaml:"lookup"`
+	Fallback ShardStrategy `yaml:"-"`
+}
+
+/*
+# range 샤딩 yaml 샘플
+ranges:
+  - { from: 1, to: 1000000, shard: 0 }
+  - { from: 1000000, to: 2000000, shard: 1 }
+lookup:
+  42: 3
+*/
+
+func LoadRangeStrategy(path string) (*RangeStrategy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRangeStrategy(b)
+}
+
+func ParseRangeStrategy(b []byte) (*RangeStrategy, error) {
+	result := new(RangeStrategy)
+	if err := yaml.Unmarshal(b, result); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result.Ranges, func(i, j int) bool {
+		return result.Ranges[i].From < result.Ranges[j].From
+	})
+
+	if err := result.validateRanges(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// validateRanges Ranges 는 From 으로 정렬되어 있어야 함. 겹치는 범위가 있으면 ShardIndex 결과가 순서에 따라 달라지므로 거부
+func (r *RangeStrategy) validateRanges() error {
+	for i, sr := range r.Ranges {
+		if sr.From >= sr.To {
+			return errors.New("xmysql: shard range must satisfy from < to")
+		}
+		if i > 0 && sr.From < r.Ranges[i-1].To {
+			prev := r.Ranges[i-1]
+			return fmt.Errorf("xmysql: shard range [%d, %d) overlaps [%d, %d)", sr.From, sr.To, prev.From, prev.To)
+		}
+	}
+
+	return nil
+}
+
+// Validate Ranges, Lookup 의 샤드 인덱스가 0 ~ shardCount-1 인지, 범위가 겹치지 않는지 확인함
+func (r *RangeStrategy) Validate(shardCount int) error {
+	if !sort.SliceIsSorted(r.Ranges, func(i, j int) bool { return r.Ranges[i].From < r.Ranges[j].From }) {
+		return errors.New("xmysql: shard ranges must be sorted by from")
+	}
+	if err := r.validateRanges(); err != nil {
+		return err
+	}
+
+	for _, sr := range r.Ranges {
+		if sr.Shard < 0 || sr.Shard >= shardCount {
+			return fmt.Errorf("xmysql: shard range [%d, %d) points to shard %d but sharder has %d", sr.From, sr.To, sr.Shard, shardCount)
+		}
+	}
+	for key, shard := range r.Lookup {
+		if shard < 0 || shard >= shardCount {
+			return fmt.Errorf("xmysql: lookup key %d points to shard %d but sharder has %d", key, shard, shardCount)
+		}
+	}
+
+	if validator, ok := r.Fallback.(ShardValidator); ok {
+		return validator.Validate(shardCount)
+	}
+
+	return nil
+}
+
+func (r *RangeStrategy) ShardIndex(key int64, shardCount int) int {
+	if shard, ok := r.Lookup[key]; ok {
+		return shard
+	}
+
+	i := sort.Search(len(r.Ranges), func(i int) bool {
+		return r.Ranges[i].To > key
+	})
+	if i < len(r.Ranges) && r.Ranges[i].From <= key {
+		return r.Ranges[i].Shard
+	}
+
+	if r.Fallback != nil {
+		return r.Fallback.ShardIndex(key, shardCount)
+	}
+
+	return -1
+}
@@ -0,0 +1,331 @@
+package xmysql
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+## 사용법 ##
+// 한 행씩 읽어서 처리. 다음 행은 루프 본문이 끝나야 읽으므로 메모리에 쌓이지 않음
+for user, err := range xmysql.Stream[User](ctx, handler, "SELECT * FROM user") {
+	if err != nil {
+		return err
+	}
+	...
+}
+
+// 채널로 받기. 받는 쪽이 느리면 buffer 만큼 찬 뒤 DB 읽기도 멈춤
+rows, errc := xmysql.StreamChan[User](ctx, handler, 128, "SELECT * FROM user")
+for user := range rows {
+	...
+}
+if err := <-errc; err != nil {
+	return err
+}
+
+// 파일로 내보내기
+count, err := handler.Export(ctx, file, xmysql.ExportJSONLines, "SELECT * FROM user WHERE level > ?", 10)
+*/
+
+type ExportFormat int
+
+const (
+	ExportCSV ExportFormat = 0 + iota
+	ExportJSONLines
+)
+
+// Stream 결과를 한 행씩 T (구조체, 구조체 포인터, 단일 컬럼 값) 로 읽어서 돌려줌. 에러가 나면 (zero, err) 를 한번 돌려주고 끝남
+// 핸들러의 기본 쿼리 타임아웃은 스트림 전체에 적용되므로 오래 걸리는 작업은 SetQueryTimeout 을 고려해야 함
+func Stream[T any](ctx context.Context, handler Querier, sqlString string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		rs, err := handler.SyncQueryContext(ctx, sqlString, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rs.Close()
+
+		columns, err := rs.curRows.Columns()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for rs.curRows.Next() {
+			var row T
+
+			v := reflect.ValueOf(&row).Elem()
+			if v.Kind() == reflect.Pointer && isStructTarget(v.Type().Elem()) {
+				v.Set(reflect.New(v.Type().Elem()))
+				v = v.Elem()
+			}
+
+			if err := rs.scanValue(v, columns); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+
+		if err := rs.curRows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// StreamFunc 행마다 fn 을 호출. fn 이 에러를 리턴하면 멈추고 그 에러를 리턴
+func StreamFunc[T any](ctx context.Context, handler Querier, sqlString string, fn func(T) error, args ...any) error {
+	for v, err := range Stream[T](ctx, handler, sqlString, args...) {
+		if err != nil {
+			return err
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StreamChan 행을 채널로 보냄. 모든 행을 보내거나 에러가 나면 rows 가 닫히고 errc 로 결과 (nil 포함) 가 한번 전달됨
+// 중간에 그만 받으려면 ctx 를 취소해야 고루틴과 커넥션이 정리됨
+func StreamChan[T any](ctx context.Context, handler Querier, buffer int, sqlString string, args ...any) (<-chan T, <-chan error) {
+	rows := make(chan T, buffer)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(rows)
+
+		for v, err := range Stream[T](ctx, handler, sqlString, args...) {
+			if err != nil {
+				errc <- err
+				return
+			}
+
+			select {
+			case rows <- v:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+
+		errc <- nil
+	}()
+
+	return rows, errc
+}
+
+// Export 결과를 행 단위로 w 에 씀. CSV 는 첫 줄이 컬럼 이름, JSON Lines 는 행마다 컬럼 순서대로의 객체
+// NULL 은 CSV 에서 빈 문자열, JSON 에서 null. 내보낸 행 수를 리턴
+func (s *Handler) Export(ctx context.Context, w io.Writer, format ExportFormat, sqlString string, args ...any) (int64, error) {
+	rs, err := s.SyncQueryContext(ctx, sqlString, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rs.Close()
+
+	columnTypes, err := rs.curRows.ColumnTypes()
+	if err != nil {
+		return 0, err
+	}
+
+	var writer rowWriter
+	switch format {
+	case ExportCSV:
+		writer = newCSVWriter(w)
+	case ExportJSONLines:
+		writer = newJSONLinesWriter(w)
+	default:
+		return 0, fmt.Errorf("xmysql: unknown export format %d", format)
+	}
+
+	columns := make([]string, len(columnTypes))
+	numeric := make([]bool, len(columnTypes))
+	for i, ct := range columnTypes {
+		columns[i] = ct.Name()
+		numeric[i] = isNumericColumn(ct)
+	}
+
+	if err := writer.header(columns); err != nil {
+		return 0, err
+	}
+
+	values := make([]any, len(columns))
+	targets := make([]any, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+
+	var count int64
+	for rs.curRows.Next() {
+		if err := rs.curRows.Scan(targets...); err != nil {
+			return count, err
+		}
+		if err := writer.row(columns, values, numeric); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	if err := rs.curRows.Err(); err != nil {
+		return count, err
+	}
+
+	return count, writer.flush()
+}
+
+func isNumericColumn(ct *sql.ColumnType) bool {
+	switch ct.DatabaseTypeName() {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "UNSIGNED TINYINT", "UNSIGNED SMALLINT", "UNSIGNED MEDIUMINT",
+		"UNSIGNED INT", "UNSIGNED BIGINT", "DECIMAL", "FLOAT", "DOUBLE", "YEAR":
+		return true
+	}
+
+	return false
+}
+
+// exportString NULL 이면 ok 가 false
+func exportString(v any) (string, bool) {
+	switch x := v.(type) {
+	case nil:
+		return "", false
+	case []byte:
+		return string(x), true
+	case string:
+		return x, true
+	case time.Time:
+		return x.Format(time.RFC3339Nano), true
+	case int64:
+		return strconv.FormatInt(x, 10), true
+	case uint64:
+		return strconv.FormatUint(x, 10), true
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(x), 'g', -1, 32), true
+	case bool:
+		return strconv.FormatBool(x), true
+	default:
+		return fmt.Sprint(x), true
+	}
+}
+
+type rowWriter interface {
+	header(columns []string) error
+	row(columns []string, values []any, numeric []bool) error
+	flush() error
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) header(columns []string) error {
+	c.record = make([]string, len(columns))
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) row(_ []string, values []any, _ []bool) error {
+	for i, v := range values {
+		c.record[i], _ = exportString(v)
+	}
+
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonLinesWriter struct {
+	w    *bufio.Writer
+	keys []string
+	sb   strings.Builder
+}
+
+func newJSONLinesWriter(w io.Writer) *jsonLinesWriter {
+	return &jsonLinesWriter{w: bufio.NewWriter(w)}
+}
+
+func (j *jsonLinesWriter) header(columns []string) error {
+	j.keys = make([]string, len(columns))
+	for i, column := range columns {
+		key, err := json.Marshal(column)
+		if err != nil {
+			return err
+		}
+		j.keys[i] = string(key)
+	}
+
+	return nil
+}
+
+func (j *jsonLinesWriter) row(_ []string, values []any, numeric []bool) error {
+	j.sb.Reset()
+	j.sb.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			j.sb.WriteByte(',')
+		}
+		j.sb.WriteString(j.keys[i])
+		j.sb.WriteByte(':')
+
+		value, err := jsonValue(v, numeric[i])
+		if err != nil {
+			return err
+		}
+		j.sb.Write(value)
+	}
+	j.sb.WriteString("}\n")
+
+	_, err := j.w.WriteString(j.sb.String())
+	return err
+}
+
+func (j *jsonLinesWriter) flush() error {
+	return j.w.Flush()
+}
+
+// jsonValue 숫자 컬럼은 텍스트 프로토콜에서 []byte 로 오므로 그대로 숫자로 씀
+func jsonValue(v any, numeric bool) ([]byte, error) {
+	s, ok := exportString(v)
+	if !ok {
+		return []byte("null"), nil
+	}
+
+	switch v.(type) {
+	case int64, uint64, float64, float32, bool:
+		return []byte(s), nil
+	}
+
+	if numeric {
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return []byte(s), nil
+		}
+	}
+
+	return json.Marshal(s)
+}
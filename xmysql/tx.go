@@ -0,0 +1,283 @@
+package xmysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"strings"
+	"time"
+)
+
+/*
+## 사용법 ##
+err := handler.WithTx(ctx, func(tx *xmysql.Tx) error {
+	if _, err := tx.Execute(ctx, "UPDATE wallet SET gold = gold - ? WHERE uid = ?", price, uid); err != nil {
+		return err
+	}
+
+	// 실패해도 바깥 트랜잭션은 유지하고 싶은 부분은 Nested 로 감쌈 (SAVEPOINT)
+	// 데드락이면 트랜잭션 전체가 이미 롤백됐으므로 Tx 가 중단 상태가 되고, Commit 이 실패해서 WithTx 가 처음부터 다시 실행함
+	if err := tx.Nested(ctx, func(tx *xmysql.Tx) error {
+		_, err := tx.Execute(ctx, "INSERT INTO purchase_log (uid, item) VALUES (?, ?)", uid, item)
+		return err
+	}); err != nil {
+		log.Printf("purchase log skipped: %v", err)
+	}
+
+	return nil
+})		// 데드락(1213), 락 대기 타임아웃(1205) 이면 처음부터 다시 실행됨
+*/
+
+const (
+	errDeadlock        uint16 = 1213
+	errLockWaitTimeout uint16 = 1205
+)
+
+// TxOptions MaxRetries 는 데드락, 락 대기 타임아웃 시 WithTx 가 다시 시도하는 횟수
+type TxOptions struct {
+	Isolation    sql.IsolationLevel
+	ReadOnly     bool
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+var defaultTxOptions = TxOptions{
+	MaxRetries:   3,
+	RetryBackoff: 50 * time.Millisecond,
+}
+
+// Tx aborted 는 데드락처럼 서버가 트랜잭션 전체를 롤백한 에러. 이후 쿼리와 Commit 은 이 에러로 실패함
+type Tx struct {
+	handler   *Handler
+	tx        *sql.Tx
+	savepoint int
+	aborted   error
+}
+
+// IsRetryable 데드락이나 락 대기 타임아웃처럼 트랜잭션을 다시 실행하면 성공할 수 있는 에러
+func IsRetryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == errDeadlock || mysqlErr.Number == errLockWaitTimeout
+	}
+
+	return false
+}
+
+// BeginTx opts 가 nil 이면 드라이버 기본값 (REPEATABLE READ, 읽기/쓰기)
+func (s *Handler) BeginTx(ctx context.Context, opts *TxOptions) (*Tx, error) {
+	var txOptions *sql.TxOptions
+	if opts != nil {
+		txOptions = &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly}
+	}
+
+	tx, err := s.sqlHandler.BeginTx(ctx, txOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{handler: s, tx: tx}, nil
+}
+
+// WithTx fn 이 nil 을 리턴하면 커밋, 에러나 panic 이면 롤백. 데드락이면 fn 을 다시 실행하므로 fn 은 재실행 가능해야 함
+func (s *Handler) WithTx(ctx context.Context, fn func(tx *Tx) error) error {
+	return s.WithTxOptions(ctx, nil, fn)
+}
+
+func (s *Handler) WithTxOptions(ctx context.Context, opts *TxOptions, fn func(tx *Tx) error) error {
+	if opts == nil {
+		opts = &defaultTxOptions
+	}
+
+	var err error
+	for attempt := 0; attempt <= opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(opts.RetryBackoff * time.Duration(attempt)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		err = s.runTx(ctx, opts, fn)
+		if err == nil || !IsRetryable(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (s *Handler) runTx(ctx context.Context, opts *TxOptions, fn func(tx *Tx) error) (result error) {
+	tx, err := s.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// abort 서버가 트랜잭션을 롤백했으면 이후 쿼리가 트랜잭션 밖에서 autocommit 으로 실행되지 않도록 중단 상태로 만듦
+func (t *Tx) abort(err error) {
+	if t.aborted == nil && IsRetryable(err) {
+		t.aborted = err
+	}
+}
+
+func (t *Tx) Query(ctx context.Context, sqlString string, args ...any) (*RecordSet, error) {
+	if t.aborted != nil {
+		return nil, t.aborted
+	}
+
+	rows, err := t.handler.txQuery(ctx, t.tx, sqlString, args)
+	if err != nil {
+		t.abort(err)
+		return nil, err
+	}
+
+	return &RecordSet{curRows: rows}, nil
+}
+
+func (t *Tx) Execute(ctx context.Context, sqlString string, args ...any) (result sql.Result, err error) {
+	if t.aborted != nil {
+		return nil, t.aborted
+	}
+	defer func() { t.abort(err) }()
+
+	ctx, finish := t.handler.startQuery(ctx, QueryKindExec, true, sqlString, args)
+	defer func() { finish(result, err) }()
+
+	if !t.handler.useStatement(args) {
+		return t.tx.ExecContext(ctx, sqlString, args...)
+	}
+
+	err = t.handler.withStatement(ctx, sqlString, func(stmt *sql.Stmt) error {
+		var execErr error
+		result, execErr = t.tx.StmtContext(ctx, stmt).ExecContext(ctx, args...)
+		return execErr
+	})
+
+	return result, err
+}
+
+// Commit 중단된 트랜잭션이면 롤백하고 중단 원인을 리턴하므로 WithTx 는 다시 시도함
+func (t *Tx) Commit() error {
+	if t.aborted != nil {
+		_ = t.tx.Rollback()
+		return t.aborted
+	}
+
+	return t.tx.Commit()
+}
+
+// Rollback 이미 끝난 트랜잭션이면 sql.ErrTxDone
+func (t *Tx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+func (t *Tx) Savepoint(ctx context.Context, name string) error {
+	_, err := t.Execute(ctx, "SAVEPOINT "+quoteIdentifier(name))
+	return err
+}
+
+func (t *Tx) RollbackTo(ctx context.Context, name string) error {
+	_, err := t.Execute(ctx, "ROLLBACK TO SAVEPOINT "+quoteIdentifier(name))
+	return err
+}
+
+func (t *Tx) ReleaseSavepoint(ctx context.Context, name string) error {
+	_, err := t.Execute(ctx, "RELEASE SAVEPOINT "+quoteIdentifier(name))
+	return err
+}
+
+// Nested 세이브포인트 안에서 fn 을 실행. fn 이 실패하면 세이브포인트까지만 롤백하고 에러를 리턴함
+// 데드락, 락 대기 타임아웃이면 세이브포인트도 사라졌으므로 롤백하지 않고 Tx 를 중단 상태로 만듦
+func (t *Tx) Nested(ctx context.Context, fn func(tx *Tx) error) error {
+	t.savepoint++
+	name := fmt.Sprintf("xmysql_sp_%d", t.savepoint)
+
+	if err := t.Savepoint(ctx, name); err != nil {
+		return err
+	}
+
+	if err := fn(t); err != nil {
+		t.abort(err)
+		if t.aborted != nil {
+			return err
+		}
+
+		if rbErr := t.RollbackTo(ctx, name); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+
+	return t.ReleaseSavepoint(ctx, name)
+}
+
+// runExecutors Transaction 의 QueryExecutor 들을 순서대로 실행. OnQuery 의 panic 은 에러로 바꿔서 리턴
+func (t *Tx) runExecutors(ctx context.Context, queries []*QueryExecutor) (result error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch r.(type) {
+			case string:
+				result = errors.New(r.(string))
+			case error:
+				result = r.(error)
+			default:
+				result = errors.New("unknown error")
+			}
+		}
+	}()
+
+	for _, executor := range queries {
+		rs, err := t.Query(ctx, executor.SqlString, executor.Args...)
+		if err != nil {
+			if executor.OnError != nil {
+				executor.OnError(err)
+			}
+			return err
+		}
+
+		err = executor.OnQuery(rs)
+		rs.Close()
+
+		if err != nil {
+			if executor.OnError != nil {
+				executor.OnError(err)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// quoteIdentifier 테이블, 컬럼 이름 등을 백틱으로 감쌈. db.table 형태는 각각 감쌈
+func quoteIdentifier(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = "`" + strings.ReplaceAll(part, "`", "``") + "`"
+	}
+
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,125 @@
+package xmysqltest
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// 미리 준비한 Rows 를 *sql.Rows 로 만들기 위한 드라이버. 쿼리 문자열로 rowsStore 의 키를 받음
+type connector struct {
+	store *rowsStore
+}
+
+type conn struct {
+	store *rowsStore
+}
+
+type rowsStore struct {
+	lock sync.Mutex
+	next uint64
+	rows map[string]*Rows
+}
+
+type rowsCursor struct {
+	rows *Rows
+	set  int
+	pos  int
+}
+
+func (s *rowsStore) put(rows *Rows) string {
+	defer s.lock.Unlock()
+
+	s.lock.Lock()
+	if s.rows == nil {
+		s.rows = make(map[string]*Rows)
+	}
+	s.next++
+	key := "xmysqltest:" + strconv.FormatUint(s.next, 10)
+	s.rows[key] = rows
+
+	return key
+}
+
+func (s *rowsStore) take(key string) (*Rows, bool) {
+	defer s.lock.Unlock()
+
+	s.lock.Lock()
+	rows, ok := s.rows[key]
+	delete(s.rows, key)
+
+	return rows, ok
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{store: c.store}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("xmysqltest: use the fake's connector")
+}
+
+func (c *conn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("xmysqltest: prepare is not supported")
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, errors.New("xmysqltest: begin is not supported")
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	rows, ok := c.store.take(query)
+	if !ok {
+		return nil, errors.New("xmysqltest: unknown rows key")
+	}
+
+	return &rowsCursor{rows: rows}, nil
+}
+
+func (r *rowsCursor) Columns() []string {
+	return r.rows.sets[r.set].columns
+}
+
+func (r *rowsCursor) Close() error {
+	return nil
+}
+
+func (r *rowsCursor) Next(dest []driver.Value) error {
+	set := r.rows.sets[r.set]
+	if r.pos >= len(set.values) {
+		return io.EOF
+	}
+
+	copy(dest, set.values[r.pos])
+	r.pos++
+
+	return nil
+}
+
+func (r *rowsCursor) HasNextResultSet() bool {
+	return r.set+1 < len(r.rows.sets)
+}
+
+func (r *rowsCursor) NextResultSet() error {
+	if !r.HasNextResultSet() {
+		return io.EOF
+	}
+
+	r.set++
+	r.pos = 0
+
+	return nil
+}
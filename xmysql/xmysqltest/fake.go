@@ -0,0 +1,459 @@
+package xmysqltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/newbiediver/golib/xmysql"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+/*
+## 사용법 ##
+func TestLoadUser(t *testing.T) {
+	fake := xmysqltest.New()
+	defer fake.Close()
+
+	fake.ExpectQuery("SELECT id, name FROM user WHERE id = ?").
+		WithArgs(1).
+		WillReturnRows(xmysqltest.NewRows("id", "name").AddRow(1, "tester"))
+	fake.ExpectExecRegex(`^UPDATE user SET login_at`).WillReturnResult(1, 0)
+
+	loadUser(fake, 1)		// xmysql.Querier 를 받는 코드. 콜백은 호출한 고루틴에서 바로 실행됨
+
+	// xmysql.QueryRows, Stream, BulkLoad, UpdateOptimistic 에도 그대로 넘길 수 있음
+	users, err := xmysql.QueryRows[User](ctx, fake, "SELECT id, name FROM user")
+
+	if err := fake.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+*/
+
+type kind int
+
+const (
+	kindQuery kind = 0 + iota
+	kindExec
+)
+
+// defaultMaxPacket MaxAllowedPacket 의 기본값 (mysql 8 기본값과 같음)
+const defaultMaxPacket = 64 << 20
+
+func (k kind) String() string {
+	if k == kindExec {
+		return "exec"
+	}
+	return "query"
+}
+
+// Rows 쿼리가 돌려줄 결과. NextResultSet 으로 결과셋을 여러 개 만들 수 있음
+type Rows struct {
+	sets []rowSet
+	err  error
+}
+
+type rowSet struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func NewRows(columns ...string) *Rows {
+	return &Rows{sets: []rowSet{{columns: columns}}}
+}
+
+// AddRow 값은 database/sql 이 드라이버 값으로 바꿀 수 있는 타입이어야 함
+func (r *Rows) AddRow(values ...any) *Rows {
+	set := &r.sets[len(r.sets)-1]
+	if len(values) != len(set.columns) && r.err == nil {
+		r.err = fmt.Errorf("xmysqltest: row has %d values for %d columns", len(values), len(set.columns))
+		return r
+	}
+
+	row := make([]driver.Value, len(values))
+	for i, v := range values {
+		converted, err := driver.DefaultParameterConverter.ConvertValue(v)
+		if err != nil && r.err == nil {
+			r.err = err
+		}
+		row[i] = converted
+	}
+	set.values = append(set.values, row)
+
+	return r
+}
+
+// NextResultSet 이후의 AddRow 는 새 결과셋에 들어감
+func (r *Rows) NextResultSet(columns ...string) *Rows {
+	r.sets = append(r.sets, rowSet{columns: columns})
+	return r
+}
+
+type anyArg struct{}
+
+// AnyArg WithArgs 에서 값에 상관없이 맞는 인자
+func AnyArg() any {
+	return anyArg{}
+}
+
+// Expectation ExpectQuery, ExpectExec 가 돌려주는 기대값
+type Expectation struct {
+	kind         kind
+	sqlString    string
+	pattern      *regexp.Regexp
+	args         []any
+	checkArgs    bool
+	rows         *Rows
+	err          error
+	rowsAffected int64
+	lastInsertID int64
+	triggered    bool
+}
+
+func (e *Expectation) WithArgs(args ...any) *Expectation {
+	e.args = args
+	e.checkArgs = true
+	return e
+}
+
+func (e *Expectation) WillReturnRows(rows *Rows) *Expectation {
+	e.rows = rows
+	return e
+}
+
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// WillReturnResult ExecCallback 과 같은 순서 (영향받은 행 수, 마지막 insert id)
+func (e *Expectation) WillReturnResult(rowsAffected, lastInsertID int64) *Expectation {
+	e.rowsAffected = rowsAffected
+	e.lastInsertID = lastInsertID
+	return e
+}
+
+func (e *Expectation) String() string {
+	if e.pattern != nil {
+		return fmt.Sprintf("%s matching %q", e.kind, e.pattern.String())
+	}
+	return fmt.Sprintf("%s %q", e.kind, e.sqlString)
+}
+
+func (e *Expectation) matches(k kind, anyKind bool, sqlString string, args []any) error {
+	if !anyKind && e.kind != k {
+		return fmt.Errorf("expected %s, got %s %q", e, k, sqlString)
+	}
+
+	if e.pattern != nil {
+		if !e.pattern.MatchString(sqlString) {
+			return fmt.Errorf("expected %s, got %q", e, sqlString)
+		}
+	} else if normalize(e.sqlString) != normalize(sqlString) {
+		return fmt.Errorf("expected %s, got %q", e, sqlString)
+	}
+
+	if !e.checkArgs {
+		return nil
+	}
+	if len(e.args) != len(args) {
+		return fmt.Errorf("%s: expected %d args, got %d", e, len(e.args), len(args))
+	}
+	for i, want := range e.args {
+		if _, ok := want.(anyArg); ok {
+			continue
+		}
+		if !equalArg(want, args[i]) {
+			return fmt.Errorf("%s: arg %d expected %v, got %v", e, i, want, args[i])
+		}
+	}
+
+	return nil
+}
+
+func normalize(sqlString string) string {
+	return strings.Join(strings.Fields(sqlString), " ")
+}
+
+// equalArg int 와 int64 처럼 드라이버 값으로 바꾸면 같아지는 인자는 같은 것으로 봄
+func equalArg(want, got any) bool {
+	w, err1 := driver.DefaultParameterConverter.ConvertValue(want)
+	g, err2 := driver.DefaultParameterConverter.ConvertValue(got)
+	if err1 != nil || err2 != nil {
+		return reflect.DeepEqual(want, got)
+	}
+
+	return reflect.DeepEqual(w, g)
+}
+
+// Fake xmysql.Querier 의 메모리 구현. 등록한 기대값과 순서대로 맞춰보고 준비한 결과를 돌려줌
+// 모든 콜백은 호출한 고루틴에서 리턴 전에 실행되므로 테스트가 결정적임
+type Fake struct {
+	lock         sync.Mutex
+	expectations []*Expectation
+	unordered    bool
+	failures     []error
+	store        *rowsStore
+	db           *sql.DB
+	maxPacket    int
+}
+
+type execResult struct {
+	rowsAffected int64
+	lastInsertID int64
+}
+
+func (r execResult) LastInsertId() (int64, error) {
+	return r.lastInsertID, nil
+}
+
+func (r execResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+var _ xmysql.Querier = (*Fake)(nil)
+
+func New() *Fake {
+	result := new(Fake)
+	result.store = new(rowsStore)
+	result.db = sql.OpenDB(&connector{store: result.store})
+	result.maxPacket = defaultMaxPacket
+
+	return result
+}
+
+// SetMaxAllowedPacket MaxAllowedPacket 이 돌려줄 값. BulkLoad 의 청크 크기를 시험할 때 사용
+func (f *Fake) SetMaxAllowedPacket(size int) {
+	f.lock.Lock()
+	f.maxPacket = size
+	f.lock.Unlock()
+}
+
+// MatchExpectationsInOrder false 면 순서와 상관없이 처음 맞는 기대값을 사용
+func (f *Fake) MatchExpectationsInOrder(inOrder bool) {
+	f.lock.Lock()
+	f.unordered = !inOrder
+	f.lock.Unlock()
+}
+
+// ExpectQuery 공백 차이를 무시하고 SQL 이 같아야 맞음
+func (f *Fake) ExpectQuery(sqlString string) *Expectation {
+	return f.expect(&Expectation{kind: kindQuery, sqlString: sqlString})
+}
+
+// ExpectQueryRegex 정규식이 SQL 의 일부와 맞으면 맞음
+func (f *Fake) ExpectQueryRegex(pattern string) *Expectation {
+	return f.expect(&Expectation{kind: kindQuery, pattern: regexp.MustCompile(pattern)})
+}
+
+func (f *Fake) ExpectExec(sqlString string) *Expectation {
+	return f.expect(&Expectation{kind: kindExec, sqlString: sqlString})
+}
+
+func (f *Fake) ExpectExecRegex(pattern string) *Expectation {
+	return f.expect(&Expectation{kind: kindExec, pattern: regexp.MustCompile(pattern)})
+}
+
+func (f *Fake) expect(e *Expectation) *Expectation {
+	f.lock.Lock()
+	f.expectations = append(f.expectations, e)
+	f.lock.Unlock()
+
+	return e
+}
+
+// ExpectationsWereMet 실행되지 않은 기대값이나 기대하지 않은 호출이 있었으면 에러
+func (f *Fake) ExpectationsWereMet() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	errs := append([]error(nil), f.failures...)
+	for _, e := range f.expectations {
+		if !e.triggered {
+			errs = append(errs, fmt.Errorf("xmysqltest: %s was not called", e))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (f *Fake) Close() error {
+	return f.db.Close()
+}
+
+// match anyKind 는 Transaction 의 QueryExecutor 처럼 query 와 exec 구분이 없는 호출
+func (f *Fake) match(k kind, anyKind bool, sqlString string, args []any) (*Expectation, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var mismatch error
+	for _, e := range f.expectations {
+		if e.triggered {
+			continue
+		}
+
+		err := e.matches(k, anyKind, sqlString, args)
+		if err == nil {
+			e.triggered = true
+			return e, nil
+		}
+		if !f.unordered {
+			mismatch = err
+			break
+		}
+	}
+
+	if mismatch == nil {
+		mismatch = fmt.Errorf("unexpected %s %q with args %v", k, sqlString, args)
+	}
+	mismatch = fmt.Errorf("xmysqltest: %w", mismatch)
+	f.failures = append(f.failures, mismatch)
+
+	return nil, mismatch
+}
+
+func (f *Fake) recordSet(rows *Rows) (*xmysql.RecordSet, error) {
+	if rows == nil {
+		rows = NewRows()
+	}
+	if rows.err != nil {
+		return nil, rows.err
+	}
+
+	sqlRows, err := f.db.Query(f.store.put(rows))
+	if err != nil {
+		return nil, err
+	}
+
+	return xmysql.NewRecordSet(sqlRows), nil
+}
+
+func (f *Fake) query(k kind, anyKind bool, sqlString string, args []any) (*xmysql.RecordSet, error) {
+	e, err := f.match(k, anyKind, sqlString, args)
+	if err != nil {
+		return nil, err
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return f.recordSet(e.rows)
+}
+
+func (f *Fake) Query(executor *xmysql.QueryExecutor) {
+	f.QueryContext(context.Background(), executor)
+}
+
+func (f *Fake) QueryContext(_ context.Context, executor *xmysql.QueryExecutor) {
+	rs, err := f.query(kindQuery, false, executor.SqlString, executor.Args)
+	if err != nil {
+		if executor.OnError != nil {
+			executor.OnError(err)
+		}
+		return
+	}
+	defer rs.Close()
+
+	if err := executor.OnQuery(rs); err != nil && executor.OnError != nil {
+		executor.OnError(err)
+	}
+}
+
+func (f *Fake) SyncQuery(sqlString string, args ...any) (*xmysql.RecordSet, error) {
+	return f.SyncQueryContext(context.Background(), sqlString, args...)
+}
+
+func (f *Fake) SyncQueryContext(_ context.Context, sqlString string, args ...any) (*xmysql.RecordSet, error) {
+	return f.query(kindQuery, false, sqlString, args)
+}
+
+func (f *Fake) SyncExecute(sqlString string, args ...any) (sql.Result, error) {
+	return f.SyncExecuteContext(context.Background(), sqlString, args...)
+}
+
+func (f *Fake) SyncExecuteContext(_ context.Context, sqlString string, args ...any) (sql.Result, error) {
+	e, err := f.match(kindExec, false, sqlString, args)
+	if err != nil {
+		return nil, err
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return execResult{rowsAffected: e.rowsAffected, lastInsertID: e.lastInsertID}, nil
+}
+
+// ExecuteBatch 각 문장을 ExpectExec 와 순서대로 맞춤. 처음 실패한 문장의 에러를 리턴함
+func (f *Fake) ExecuteBatch(ctx context.Context, statements ...xmysql.Statement) error {
+	for _, statement := range statements {
+		if _, err := f.SyncExecuteContext(ctx, statement.SQL, statement.Args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *Fake) MaxAllowedPacket(context.Context) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.maxPacket, nil
+}
+
+func (f *Fake) Execute(queryString string, execCallback xmysql.ExecCallback, errCallback xmysql.ErrorCallback, args ...any) {
+	f.ExecuteContext(context.Background(), queryString, execCallback, errCallback, args...)
+}
+
+func (f *Fake) ExecuteContext(_ context.Context, queryString string, execCallback xmysql.ExecCallback, errCallback xmysql.ErrorCallback, args ...any) {
+	e, err := f.match(kindExec, false, queryString, args)
+	if err == nil {
+		err = e.err
+	}
+
+	if err != nil {
+		if errCallback != nil {
+			errCallback(err)
+		}
+		return
+	}
+
+	if execCallback != nil {
+		execCallback(e.rowsAffected, e.lastInsertID)
+	}
+}
+
+func (f *Fake) Transaction(onCommit xmysql.CommitCallback, onRollback xmysql.RollbackCallback, queries ...*xmysql.QueryExecutor) {
+	f.TransactionContext(context.Background(), onCommit, onRollback, queries...)
+}
+
+// TransactionContext 각 QueryExecutor 는 ExpectQuery, ExpectExec 어느 쪽과도 맞춰짐
+// 하나라도 에러가 나면 onRollback, 아니면 onCommit 이 호출됨
+func (f *Fake) TransactionContext(_ context.Context, onCommit xmysql.CommitCallback, onRollback xmysql.RollbackCallback, queries ...*xmysql.QueryExecutor) {
+	if onCommit == nil || onRollback == nil {
+		panic("[DBTransaction] Commit or Rollback callback is nil")
+	}
+
+	for _, executor := range queries {
+		rs, err := f.query(kindQuery, true, executor.SqlString, executor.Args)
+		if err == nil {
+			err = executor.OnQuery(rs)
+			rs.Close()
+		}
+
+		if err != nil {
+			if executor.OnError != nil {
+				executor.OnError(err)
+			}
+			onRollback(err)
+			return
+		}
+	}
+
+	onCommit()
+}
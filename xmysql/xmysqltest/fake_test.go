@@ -0,0 +1,98 @@
+package xmysqltest
+
+import (
+	"context"
+	"errors"
+	"github.com/newbiediver/golib/xmysql"
+	"testing"
+)
+
+type user struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestQueryRowsAndStream(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	defer fake.Close()
+
+	fake.ExpectQuery("SELECT id, name FROM user WHERE level > ?").
+		WithArgs(10).
+		WillReturnRows(NewRows("id", "name").AddRow(1, "a").AddRow(2, "b"))
+	fake.ExpectQuery("SELECT id, name FROM user").
+		WillReturnRows(NewRows("id", "name").AddRow(3, "c"))
+
+	users, err := xmysql.QueryRows[user](ctx, fake, "SELECT id, name FROM user WHERE level > ?", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[1] != (user{ID: 2, Name: "b"}) {
+		t.Fatalf("QueryRows = %+v", users)
+	}
+
+	var streamed []user
+	for u, err := range xmysql.Stream[user](ctx, fake, "SELECT id, name FROM user") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		streamed = append(streamed, u)
+	}
+	if len(streamed) != 1 || streamed[0].Name != "c" {
+		t.Fatalf("Stream = %+v", streamed)
+	}
+
+	if err := fake.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBulkLoad(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	defer fake.Close()
+
+	fake.ExpectExec("INSERT INTO `user` (`id`, `name`) VALUES (?, ?), (?, ?)").WithArgs(1, "a", 2, "b").WillReturnResult(2, 0)
+	fake.ExpectExec("INSERT INTO `user` (`id`, `name`) VALUES (?, ?)").WithArgs(3, "c").WillReturnError(errors.New("duplicate"))
+
+	rows := []user{{1, "a"}, {2, "b"}, {3, "c"}}
+	loaded, err := xmysql.BulkLoadSlice(ctx, fake, xmysql.BulkLoadOptions{Table: "user", ChunkRows: 2}, rows)
+	if err == nil || loaded != 2 {
+		t.Fatalf("BulkLoadSlice = %d, %v", loaded, err)
+	}
+
+	if err := fake.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateOptimistic(t *testing.T) {
+	ctx := context.Background()
+	fake := New()
+	defer fake.Close()
+
+	update := "UPDATE `wallet` SET `gold` = ?, `version` = `version` + 1 WHERE `uid` = ? AND `version` = ?"
+	fake.ExpectExec(update).WithArgs(90, 7, 0).WillReturnResult(0, 0)
+	fake.ExpectExec(update).WithArgs(90, 7, 1).WillReturnResult(1, 0)
+
+	opts := &xmysql.OptimisticOptions{MaxAttempts: 3}
+	err := xmysql.UpdateOptimistic(ctx, fake, opts, func(ctx context.Context, attempt int) (xmysql.StatementBuilder, error) {
+		return xmysql.Update("wallet").Set("gold", 90).Where(xmysql.Eq("uid", 7)).Versioned("version", attempt), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var conflict *xmysql.ConflictError
+	fake.ExpectExec(update).WithArgs(90, 7, 0).WillReturnResult(0, 0)
+	err = xmysql.UpdateOptimistic(ctx, fake, &xmysql.OptimisticOptions{MaxAttempts: 1}, func(ctx context.Context, attempt int) (xmysql.StatementBuilder, error) {
+		return xmysql.Update("wallet").Set("gold", 90).Where(xmysql.Eq("uid", 7)).Versioned("version", attempt), nil
+	})
+	if !errors.As(err, &conflict) {
+		t.Fatalf("UpdateOptimistic err = %v, want ConflictError", err)
+	}
+
+	if err := fake.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,114 @@
+package xredis
+
+import (
+	"encoding/json"
+	"github.com/redis/go-redis/v9"
+)
+
+/*
+## 사용법 ##
+값은 모두 JSON 으로 저장되므로 (HashSet 과 같은 규칙) 읽을 때 Decode 계열로 타입을 지정함
+
+handler := xredis.AllocateHandler()
+defer xredis.ReleaseHandler(handler)
+
+_ = handler.Set(ctx, "user:1", user, time.Hour)
+user, err := xredis.GetAs[User](ctx, handler, "user:1")
+if errors.Is(err, xredis.ErrNil) {
+	// 키가 없음
+}
+
+ranking, err := xredis.DecodeScored[int64](handler.ZRevRangeWithScores(ctx, "ranking", 0, 9))
+items, err := xredis.DecodeMap[Item](handler.HGetAll(ctx, "inventory:1"))
+*/
+
+// ErrNil 키나 필드가 없을 때 (redis.Nil)
+var ErrNil = redis.Nil
+
+// ScoredMember sorted set 의 멤버와 점수. Member 는 저장된 JSON 그대로
+type ScoredMember struct {
+	Member string
+	Score  float64
+}
+
+// Scored DecodeScored 로 디코딩한 sorted set 멤버
+type Scored[T any] struct {
+	Member T
+	Score  float64
+}
+
+func marshal(value any) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func marshalAll(values []any) ([]any, error) {
+	result := make([]any, len(values))
+	for i, value := range values {
+		b, err := marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = b
+	}
+
+	return result, nil
+}
+
+// Decode 명령의 (값, 에러) 를 그대로 받아서 T 로 디코딩. err 가 있으면 그대로 리턴
+func Decode[T any](value string, err error) (T, error) {
+	var result T
+	if err != nil {
+		return result, err
+	}
+
+	err = json.Unmarshal([]byte(value), &result)
+	return result, err
+}
+
+func DecodeAll[T any](values []string, err error) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]T, len(values))
+	for i, value := range values {
+		if err := json.Unmarshal([]byte(value), &result[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func DecodeMap[T any](values map[string]string, err error) (map[string]T, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]T, len(values))
+	for key, value := range values {
+		var v T
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return nil, err
+		}
+		result[key] = v
+	}
+
+	return result, nil
+}
+
+func DecodeScored[T any](values []ScoredMember, err error) ([]Scored[T], error) {
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Scored[T], len(values))
+	for i, value := range values {
+		if err := json.Unmarshal([]byte(value.Member), &result[i].Member); err != nil {
+			return nil, err
+		}
+		result[i].Score = value.Score
+	}
+
+	return result, nil
+}
@@ -3,9 +3,11 @@ package xredis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/newbiediver/golib/container"
 	"github.com/redis/go-redis/v9"
+	"time"
 )
 
 type endpoint struct {
@@ -19,32 +21,62 @@ type Handler struct {
 	redisHandler *redis.Client
 }
 
+const (
+	// 이 시간 이상 쉬었던 핸들러는 꺼낼 때 ping 으로 확인함
+	validateIdleTime = 30 * time.Second
+)
+
+var (
+	ErrNoHandlers = errors.New("xredis: CreateHandlers has not been called")
+)
+
 var (
 	ep              endpoint
-	managedHandlers container.SafeQueue[*Handler]
+	managedHandlers *container.Pool[*Handler]
 )
 
+// CreateHandlers io 개의 핸들러를 미리 만들어 둠. 부족하면 제한 없이 더 만듦
 func CreateHandlers(address, pwd string, port, index, io int) error {
+	return CreateHandlersWithLimit(address, pwd, port, index, io, 0)
+}
+
+// CreateHandlersWithLimit maxTotal 개까지만 만들고, 모두 사용중이면 AllocateHandler 가 반납될 때까지 대기함 (0 이면 제한 없음)
+func CreateHandlersWithLimit(address, pwd string, port, index, minIdle, maxTotal int) error {
 	ep.address = address
 	ep.pwd = pwd
 	ep.port = port
 	ep.index = index
 
-	for i := 0; i < io; i++ {
-		handler, err := createHandler()
-		if err != nil {
-			return err
-		}
-
-		managedHandlers.Push(handler)
+	pool, err := container.NewPool(container.PoolOptions[*Handler]{
+		Factory:  createHandler,
+		Validate: validateHandler,
+		Destroy: func(h *Handler) {
+			h.Close()
+		},
+		MinIdle:     minIdle,
+		MaxTotal:    maxTotal,
+		MaxIdleTime: 5 * time.Minute,
+	})
+	if err != nil {
+		return err
 	}
 
+	managedHandlers = pool
 	return nil
 }
 
-func createHandler() (*Handler, error) {
-	ctx := context.Background()
+func validateHandler(h *Handler, idle time.Duration) bool {
+	if idle < validateIdleTime {
+		return true
+	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return h.redisHandler.Ping(ctx).Err() == nil
+}
+
+func createHandler(ctx context.Context) (*Handler, error) {
 	handler := new(Handler)
 	handler.redisHandler = redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("%s:%d", ep.address, ep.port),
@@ -53,6 +85,7 @@ func createHandler() (*Handler, error) {
 	})
 
 	if err := handler.redisHandler.Ping(ctx).Err(); err != nil {
+		_ = handler.redisHandler.Close()
 		return nil, err
 	}
 
@@ -60,32 +93,62 @@ func createHandler() (*Handler, error) {
 }
 
 func AllocateHandler() *Handler {
-	if handler, ok := managedHandlers.Pop(); ok {
-		return handler
+	handler, err := AllocateHandlerContext(context.Background())
+	if err != nil {
+		panic(err)
 	}
 
-	if managedHandlers.Len() == 0 {
-		handler, err := createHandler()
-		if err != nil {
-			panic(err)
-		}
+	return handler
+}
 
-		return handler
+// AllocateHandlerContext 사용 가능한 핸들러가 없으면 ctx 만큼 대기
+func AllocateHandlerContext(ctx context.Context) (*Handler, error) {
+	if managedHandlers == nil {
+		return nil, ErrNoHandlers
 	}
 
-	return nil
+	return managedHandlers.Acquire(ctx)
 }
 
+// ReleaseHandler CreateHandlers 전이라 돌려줄 풀이 없으면 핸들러를 닫음
 func ReleaseHandler(h *Handler) {
-	managedHandlers.Push(h)
+	if managedHandlers == nil {
+		if h != nil {
+			h.Close()
+		}
+		return
+	}
+
+	managedHandlers.Release(h)
 }
 
-func FlushHandlers() {
-	for managedHandlers.Len() > 0 {
-		if handler, ok := managedHandlers.Pop(); ok {
-			handler.Close()
+// DiscardHandler 연결이 망가진 핸들러를 풀에 돌려주지 않고 닫음
+func DiscardHandler(h *Handler) {
+	if managedHandlers == nil {
+		if h != nil {
+			h.Close()
 		}
+		return
 	}
+
+	managedHandlers.Discard(h)
+}
+
+func PoolStats() container.PoolStats {
+	if managedHandlers == nil {
+		return container.PoolStats{}
+	}
+
+	return managedHandlers.Stats()
+}
+
+// FlushHandlers CreateHandlers 를 호출하지 않았으면 아무것도 하지 않음
+func FlushHandlers() {
+	if managedHandlers == nil {
+		return
+	}
+
+	managedHandlers.Close()
 }
 
 func (h *Handler) Close() {
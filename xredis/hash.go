@@ -0,0 +1,42 @@
+package xredis
+
+import (
+	"context"
+)
+
+// HGet 저장된 JSON 그대로. 필드가 없으면 ErrNil
+func (h *Handler) HGet(ctx context.Context, key, field string) (string, error) {
+	return h.redisHandler.HGet(ctx, key, field).Result()
+}
+
+// HGetAs HGet 한 값을 T 로 디코딩
+func HGetAs[T any](ctx context.Context, h *Handler, key, field string) (T, error) {
+	return Decode[T](h.HGet(ctx, key, field))
+}
+
+// HGetAll 키가 없으면 빈 맵
+func (h *Handler) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return h.redisHandler.HGetAll(ctx, key).Result()
+}
+
+// HMSet 여러 필드를 한번에 저장
+func (h *Handler) HMSet(ctx context.Context, key string, values map[string]any) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(values)*2)
+	for field, value := range values {
+		b, err := marshal(value)
+		if err != nil {
+			return err
+		}
+		args = append(args, field, b)
+	}
+
+	return h.redisHandler.HSet(ctx, key, args...).Err()
+}
+
+func (h *Handler) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	return h.redisHandler.HDel(ctx, key, fields...).Result()
+}
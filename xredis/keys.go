@@ -0,0 +1,62 @@
+package xredis
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// NoExpiration TTL 에서 만료가 없는 키
+const NoExpiration time.Duration = -1
+
+func (h *Handler) Del(ctx context.Context, keys ...string) (int64, error) {
+	return h.redisHandler.Del(ctx, keys...).Result()
+}
+
+func (h *Handler) ExistsContext(ctx context.Context, key string) (bool, error) {
+	exists, err := h.redisHandler.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return exists == 1, nil
+}
+
+// Expire 키가 없으면 false
+func (h *Handler) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return h.redisHandler.Expire(ctx, key, ttl).Result()
+}
+
+// TTL 키가 없으면 ErrNil, 만료가 없으면 NoExpiration
+func (h *Handler) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := h.redisHandler.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	switch ttl {
+	case -2:
+		return 0, ErrNil
+	case -1:
+		return NoExpiration, nil
+	}
+
+	return ttl, nil
+}
+
+// Scan match 패턴에 맞는 키를 SCAN 으로 조금씩 가져옴 (KEYS 와 달리 서버를 막지 않음)
+// count 는 한번에 가져올 개수의 힌트이고, 같은 키가 두 번 나올 수 있음
+func (h *Handler) Scan(ctx context.Context, match string, count int64) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		it := h.redisHandler.Scan(ctx, 0, match, count).Iterator()
+		for it.Next(ctx) {
+			if !yield(it.Val(), nil) {
+				return
+			}
+		}
+
+		if err := it.Err(); err != nil {
+			yield("", err)
+		}
+	}
+}
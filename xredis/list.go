@@ -0,0 +1,45 @@
+package xredis
+
+import (
+	"context"
+)
+
+func (h *Handler) LPush(ctx context.Context, key string, values ...any) (int64, error) {
+	encoded, err := marshalAll(values)
+	if err != nil {
+		return 0, err
+	}
+
+	return h.redisHandler.LPush(ctx, key, encoded...).Result()
+}
+
+func (h *Handler) RPush(ctx context.Context, key string, values ...any) (int64, error) {
+	encoded, err := marshalAll(values)
+	if err != nil {
+		return 0, err
+	}
+
+	return h.redisHandler.RPush(ctx, key, encoded...).Result()
+}
+
+// LPop 비어있으면 ErrNil
+func (h *Handler) LPop(ctx context.Context, key string) (string, error) {
+	return h.redisHandler.LPop(ctx, key).Result()
+}
+
+func (h *Handler) RPop(ctx context.Context, key string) (string, error) {
+	return h.redisHandler.RPop(ctx, key).Result()
+}
+
+// LRange stop 이 -1 이면 끝까지
+func (h *Handler) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return h.redisHandler.LRange(ctx, key, start, stop).Result()
+}
+
+func (h *Handler) LTrim(ctx context.Context, key string, start, stop int64) error {
+	return h.redisHandler.LTrim(ctx, key, start, stop).Err()
+}
+
+func (h *Handler) LLen(ctx context.Context, key string) (int64, error) {
+	return h.redisHandler.LLen(ctx, key).Result()
+}
@@ -0,0 +1,87 @@
+package xredis
+
+import (
+	"context"
+	"errors"
+	"github.com/newbiediver/golib/scheduler"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+var (
+	renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// Locker scheduler.Locker 의 redis 구현. SET NX PX 로 락을 잡고 토큰이 일치할 때만 연장/해제함
+type Locker struct{}
+
+type lease struct {
+	key   string
+	token string
+}
+
+func NewLocker() *Locker {
+	return new(Locker)
+}
+
+func (l *Locker) Acquire(ctx context.Context, key string, ttl time.Duration) (scheduler.Lease, error) {
+	handler, err := AllocateHandlerContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer ReleaseHandler(handler)
+
+	token := scheduler.NewLockToken()
+	ok, err := handler.redisHandler.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, scheduler.ErrLockNotAcquired
+	}
+
+	return &lease{key: key, token: token}, nil
+}
+
+func (l *lease) Renew(ctx context.Context, ttl time.Duration) error {
+	handler, err := AllocateHandlerContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer ReleaseHandler(handler)
+
+	result, err := renewScript.Run(ctx, handler.redisHandler, []string{l.key}, l.token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if result == 0 {
+		return scheduler.ErrLockLost
+	}
+
+	return nil
+}
+
+func (l *lease) Release(ctx context.Context) error {
+	handler, err := AllocateHandlerContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer ReleaseHandler(handler)
+
+	err = releaseScript.Run(ctx, handler.redisHandler, []string{l.key}, l.token).Err()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	return nil
+}
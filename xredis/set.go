@@ -0,0 +1,40 @@
+package xredis
+
+import (
+	"context"
+)
+
+func (h *Handler) SAdd(ctx context.Context, key string, members ...any) (int64, error) {
+	encoded, err := marshalAll(members)
+	if err != nil {
+		return 0, err
+	}
+
+	return h.redisHandler.SAdd(ctx, key, encoded...).Result()
+}
+
+func (h *Handler) SRem(ctx context.Context, key string, members ...any) (int64, error) {
+	encoded, err := marshalAll(members)
+	if err != nil {
+		return 0, err
+	}
+
+	return h.redisHandler.SRem(ctx, key, encoded...).Result()
+}
+
+func (h *Handler) SIsMember(ctx context.Context, key string, member any) (bool, error) {
+	b, err := marshal(member)
+	if err != nil {
+		return false, err
+	}
+
+	return h.redisHandler.SIsMember(ctx, key, b).Result()
+}
+
+func (h *Handler) SMembers(ctx context.Context, key string) ([]string, error) {
+	return h.redisHandler.SMembers(ctx, key).Result()
+}
+
+func (h *Handler) SCard(ctx context.Context, key string) (int64, error) {
+	return h.redisHandler.SCard(ctx, key).Result()
+}
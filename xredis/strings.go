@@ -0,0 +1,49 @@
+package xredis
+
+import (
+	"context"
+	"time"
+)
+
+// Set ttl 이 0 이면 만료 없음
+func (h *Handler) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	b, err := marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return h.redisHandler.Set(ctx, key, b, ttl).Err()
+}
+
+// SetNX 키가 없을 때만 저장하고 저장했으면 true
+func (h *Handler) SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	b, err := marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	return h.redisHandler.SetNX(ctx, key, b, ttl).Result()
+}
+
+// Get 저장된 JSON 그대로. 키가 없으면 ErrNil
+func (h *Handler) Get(ctx context.Context, key string) (string, error) {
+	return h.redisHandler.Get(ctx, key).Result()
+}
+
+// GetAs Get 한 값을 T 로 디코딩
+func GetAs[T any](ctx context.Context, h *Handler, key string) (T, error) {
+	return Decode[T](h.Get(ctx, key))
+}
+
+// Incr 숫자는 JSON 으로 저장해도 그대로 숫자 문자열이므로 Set 한 값에 바로 쓸 수 있음
+func (h *Handler) Incr(ctx context.Context, key string) (int64, error) {
+	return h.redisHandler.Incr(ctx, key).Result()
+}
+
+func (h *Handler) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return h.redisHandler.IncrBy(ctx, key, value).Result()
+}
+
+func (h *Handler) Decr(ctx context.Context, key string) (int64, error) {
+	return h.redisHandler.Decr(ctx, key).Result()
+}
@@ -0,0 +1,105 @@
+package xredis
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+)
+
+// ZMember ZAdd 에 넘기는 멤버. Member 는 JSON 으로 저장됨
+type ZMember struct {
+	Member any
+	Score  float64
+}
+
+func (h *Handler) ZAdd(ctx context.Context, key string, members ...ZMember) (int64, error) {
+	encoded := make([]redis.Z, len(members))
+	for i, m := range members {
+		b, err := marshal(m.Member)
+		if err != nil {
+			return 0, err
+		}
+		encoded[i] = redis.Z{Score: m.Score, Member: b}
+	}
+
+	return h.redisHandler.ZAdd(ctx, key, encoded...).Result()
+}
+
+func (h *Handler) ZIncrBy(ctx context.Context, key string, increment float64, member any) (float64, error) {
+	b, err := marshal(member)
+	if err != nil {
+		return 0, err
+	}
+
+	return h.redisHandler.ZIncrBy(ctx, key, increment, string(b)).Result()
+}
+
+func (h *Handler) ZRem(ctx context.Context, key string, members ...any) (int64, error) {
+	encoded, err := marshalAll(members)
+	if err != nil {
+		return 0, err
+	}
+
+	return h.redisHandler.ZRem(ctx, key, encoded...).Result()
+}
+
+// ZRange 점수 오름차순. stop 이 -1 이면 끝까지
+func (h *Handler) ZRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return h.redisHandler.ZRange(ctx, key, start, stop).Result()
+}
+
+func (h *Handler) ZRangeWithScores(ctx context.Context, key string, start, stop int64) ([]ScoredMember, error) {
+	return scoredMembers(h.redisHandler.ZRangeWithScores(ctx, key, start, stop).Result())
+}
+
+// ZRevRangeWithScores 점수 내림차순 (랭킹)
+func (h *Handler) ZRevRangeWithScores(ctx context.Context, key string, start, stop int64) ([]ScoredMember, error) {
+	return scoredMembers(h.redisHandler.ZRevRangeWithScores(ctx, key, start, stop).Result())
+}
+
+// ZRank 점수 오름차순 순위 (0 부터). 멤버가 없으면 ErrNil
+func (h *Handler) ZRank(ctx context.Context, key string, member any) (int64, error) {
+	b, err := marshal(member)
+	if err != nil {
+		return 0, err
+	}
+
+	return h.redisHandler.ZRank(ctx, key, string(b)).Result()
+}
+
+// ZRevRank 점수 내림차순 순위 (0 부터). 멤버가 없으면 ErrNil
+func (h *Handler) ZRevRank(ctx context.Context, key string, member any) (int64, error) {
+	b, err := marshal(member)
+	if err != nil {
+		return 0, err
+	}
+
+	return h.redisHandler.ZRevRank(ctx, key, string(b)).Result()
+}
+
+// ZScore 멤버가 없으면 ErrNil
+func (h *Handler) ZScore(ctx context.Context, key string, member any) (float64, error) {
+	b, err := marshal(member)
+	if err != nil {
+		return 0, err
+	}
+
+	return h.redisHandler.ZScore(ctx, key, string(b)).Result()
+}
+
+func (h *Handler) ZCard(ctx context.Context, key string) (int64, error) {
+	return h.redisHandler.ZCard(ctx, key).Result()
+}
+
+func scoredMembers(values []redis.Z, err error) ([]ScoredMember, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ScoredMember, len(values))
+	for i, value := range values {
+		member, _ := value.Member.(string)
+		result[i] = ScoredMember{Member: member, Score: value.Score}
+	}
+
+	return result, nil
+}